@@ -3,16 +3,26 @@ package main
 import (
 	"context"
 	"fmt"
+	"golang-hexagon/internal/adapter/auth/hasher"
+	"golang-hexagon/internal/adapter/auth/jwt"
 	"golang-hexagon/internal/adapter/auth/paseto"
+	"golang-hexagon/internal/adapter/auth/totp"
 	"golang-hexagon/internal/adapter/config"
 	"golang-hexagon/internal/adapter/handler/http"
+	"golang-hexagon/internal/adapter/handler/worker"
 	"golang-hexagon/internal/adapter/logger"
+	"golang-hexagon/internal/adapter/notifier/file"
+	"golang-hexagon/internal/adapter/notifier/smtp"
+	publisherrmq "golang-hexagon/internal/adapter/publisher/rmq"
 	"golang-hexagon/internal/adapter/storage/postgres"
 	"golang-hexagon/internal/adapter/storage/postgres/repository"
 	"golang-hexagon/internal/adapter/storage/redis"
+	"golang-hexagon/internal/core/port"
 	"golang-hexagon/internal/core/service"
 	"log/slog"
 	"os"
+	"strconv"
+	"time"
 )
 
 // @title						Go Hexagon Arch POC (Proof of concept) API
@@ -71,41 +81,202 @@ func main() {
 	}
 	defer func() {
 		if err := cache.Close(); err != nil {
-			slog.Error("Error closing cache connection", err)
+			slog.Error("Error closing cache connection", "error", err)
 		}
 	}()
 
 	slog.Info("Successfully connected to the cache server")
 
 	// Init token service
-	token, err := paseto.New(conf.Token)
+	var token port.TokenService
+	switch conf.Token.Type {
+	case config.TokenTypeJWT:
+		token, err = jwt.New(conf.Token)
+	default:
+		token, err = paseto.New(conf.Token)
+	}
 	if err != nil {
 		slog.Error("Error initializing token service", "error", err)
 		os.Exit(1)
 	}
 
+	// Init password hasher
+	passwordHasher, err := hasher.New(conf.Hasher)
+	if err != nil {
+		slog.Error("Error initializing password hasher", "error", err)
+		os.Exit(1)
+	}
+
+	accessTTL, err := time.ParseDuration(conf.Token.Duration)
+	if err != nil {
+		slog.Error("Error parsing access token duration", "error", err)
+		os.Exit(1)
+	}
+
+	refreshTTL, err := time.ParseDuration(conf.Token.RefreshDuration)
+	if err != nil {
+		slog.Error("Error parsing refresh token duration", "error", err)
+		os.Exit(1)
+	}
+
+	loginPolicy, err := config.NewLoginPolicy(conf.Auth)
+	if err != nil {
+		slog.Error("Error parsing login policy", "error", err)
+		os.Exit(1)
+	}
+
+	passwordPolicy, err := config.NewPasswordPolicy(conf.Password)
+	if err != nil {
+		slog.Error("Error parsing password policy", "error", err)
+		os.Exit(1)
+	}
+
+	resetTTL, err := time.ParseDuration(conf.Auth.PasswordResetDuration)
+	if err != nil {
+		slog.Error("Error parsing password reset duration", "error", err)
+		os.Exit(1)
+	}
+
+	verificationTTL, err := time.ParseDuration(conf.Auth.VerificationDuration)
+	if err != nil {
+		slog.Error("Error parsing email verification duration", "error", err)
+		os.Exit(1)
+	}
+
+	resendInterval, err := time.ParseDuration(conf.Auth.VerificationResend)
+	if err != nil {
+		slog.Error("Error parsing email verification resend interval", "error", err)
+		os.Exit(1)
+	}
+
+	outboxPolicy, err := config.NewOutboxPolicy(conf.Events)
+	if err != nil {
+		slog.Error("Error parsing outbox relay settings", "error", err)
+		os.Exit(1)
+	}
+
+	relayInterval, err := time.ParseDuration(conf.Events.RelayInterval)
+	if err != nil {
+		slog.Error("Error parsing outbox relay interval", "error", err)
+		os.Exit(1)
+	}
+
+	cleanupInterval, err := time.ParseDuration(conf.Events.CleanupInterval)
+	if err != nil {
+		slog.Error("Error parsing outbox cleanup interval", "error", err)
+		os.Exit(1)
+	}
+
+	deletedRetention, err := time.ParseDuration(conf.Users.DeletedRetention)
+	if err != nil {
+		slog.Error("Error parsing deleted users retention", "error", err)
+		os.Exit(1)
+	}
+
+	purgeInterval, err := time.ParseDuration(conf.Users.PurgeInterval)
+	if err != nil {
+		slog.Error("Error parsing deleted users purge interval", "error", err)
+		os.Exit(1)
+	}
+
+	purgeEnabled, err := strconv.ParseBool(conf.Users.PurgeEnabled)
+	if err != nil {
+		slog.Error("Error parsing deleted users purge setting", "error", err)
+		os.Exit(1)
+	}
+
+	requireIfMatch, err := strconv.ParseBool(conf.HTTP.RequireIfMatch)
+	if err != nil {
+		slog.Error("Error parsing If-Match requirement", "error", err)
+		os.Exit(1)
+	}
+
+	// Init notifier
+	var notifier port.Notifier
+	switch conf.Notifier.Type {
+	case config.NotifierTypeSMTP:
+		notifier = smtp.New(conf.Notifier)
+	default:
+		notifier = file.New(conf.Notifier)
+	}
+
+	// Init event publisher, the outbox relay marks events as sent once the broker has them
+	events, err := publisherrmq.New(conf.Events)
+	if err != nil {
+		slog.Error("Error initializing event publisher", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := events.Close(); err != nil {
+			slog.Error("Error closing event publisher", "error", err)
+		}
+	}()
+
 	// Dependency injection
+	// Outbox
+	outboxRepo := repository.NewOutboxRepository(db)
+	outboxService := service.NewOutboxService(outboxRepo, events, db, outboxPolicy)
+
+	// Role
+	roleRepo := repository.NewRoleRepository(db)
+	authorizer := service.NewAuthorizer(roleRepo, cache)
+	roleService := service.NewRoleService(roleRepo, cache)
+	roleHandler := http.NewRoleHandler(roleService, authorizer)
+
 	// User
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo, cache)
-	userHandler := http.NewUserHandler(userService)
+	verificationService := service.NewVerificationService(userRepo, notifier, cache, outboxRepo, db, verificationTTL, resendInterval, conf.Auth.VerificationURL)
+	verificationHandler := http.NewVerificationHandler(verificationService)
 
 	// Auth
-	authService := service.NewAuthService(userRepo, token)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	totpService := totp.New(conf.Auth)
+	authService := service.NewAuthService(userRepo, passwordHasher, token, refreshTokenRepo, mfaRepo, totpService, cache, outboxRepo, db, accessTTL, refreshTTL, loginPolicy)
 	authHandler := http.NewAuthHandler(authService)
 
+	// User management, password changes revoke the other sessions of the user
+	userService := service.NewUserService(userRepo, passwordHasher, cache, verificationService, authService, refreshTokenRepo, outboxRepo, db, accessTTL, deletedRetention, passwordPolicy)
+	userHandler := http.NewUserHandler(userService, authorizer, requireIfMatch)
+
+	// MFA
+	mfaService := service.NewMFAService(userRepo, mfaRepo, totpService, authService, cache)
+	mfaHandler := http.NewMFAHandler(mfaService)
+
+	// Password
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	passwordService := service.NewPasswordService(userRepo, passwordHasher, passwordResetRepo, authService, notifier, cache, outboxRepo, db, resetTTL, conf.Auth.PasswordResetURL, passwordPolicy)
+	passwordHandler := http.NewPasswordHandler(passwordService)
+
+	// Key
+	keyHandler := http.NewKeyHandler(token)
+
 	// Init router
 	router, err := http.NewRouter(
 		conf,
-		token,
+		authService,
+		authorizer,
 		*userHandler,
 		*authHandler,
+		*passwordHandler,
+		*verificationHandler,
+		*mfaHandler,
+		*roleHandler,
+		*keyHandler,
 	)
 	if err != nil {
 		slog.Error("Error initializing router", "error", err)
 		os.Exit(1)
 	}
 
+	// Start outbox relay
+	go worker.RunOutboxRelay(ctx, outboxService, outboxPolicy.BatchSize, relayInterval, cleanupInterval)
+
+	// Start purge of deleted users if this application owns it
+	if purgeEnabled {
+		go worker.RunUserPurge(ctx, userService, purgeInterval)
+	}
+
 	// Start server
 	listenAddr := fmt.Sprintf("%s:%s", conf.HTTP.URL, conf.HTTP.Port)
 	slog.Info("Starting the Config server", "listen_address", listenAddr)
@@ -2,16 +2,29 @@ package main
 
 import (
 	"context"
+	"golang-hexagon/internal/adapter/auth/hasher"
+	"golang-hexagon/internal/adapter/auth/jwt"
 	"golang-hexagon/internal/adapter/auth/paseto"
+	"golang-hexagon/internal/adapter/auth/totp"
 	"golang-hexagon/internal/adapter/config"
 	"golang-hexagon/internal/adapter/handler/rmq"
+	"golang-hexagon/internal/adapter/handler/worker"
 	"golang-hexagon/internal/adapter/logger"
+	"golang-hexagon/internal/adapter/notifier/file"
+	"golang-hexagon/internal/adapter/notifier/smtp"
+	publisherrmq "golang-hexagon/internal/adapter/publisher/rmq"
 	"golang-hexagon/internal/adapter/storage/postgres"
 	"golang-hexagon/internal/adapter/storage/postgres/repository"
 	"golang-hexagon/internal/adapter/storage/redis"
+	"golang-hexagon/internal/core/port"
 	"golang-hexagon/internal/core/service"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -55,30 +68,205 @@ func main() {
 	}
 	defer func() {
 		if err := cache.Close(); err != nil {
-			slog.Error("Error closing cache connection", err)
+			slog.Error("Error closing cache connection", "error", err)
 		}
 	}()
 
 	slog.Info("Successfully connected to the cache server")
 
 	// Init token service
-	token, err := paseto.New(conf.Token)
+	var token port.TokenService
+	switch conf.Token.Type {
+	case config.TokenTypeJWT:
+		token, err = jwt.New(conf.Token)
+	default:
+		token, err = paseto.New(conf.Token)
+	}
 	if err != nil {
 		slog.Error("Error initializing token service", "error", err)
 		os.Exit(1)
 	}
 
+	// Init password hasher
+	passwordHasher, err := hasher.New(conf.Hasher)
+	if err != nil {
+		slog.Error("Error initializing password hasher", "error", err)
+		os.Exit(1)
+	}
+
+	accessTTL, err := time.ParseDuration(conf.Token.Duration)
+	if err != nil {
+		slog.Error("Error parsing access token duration", "error", err)
+		os.Exit(1)
+	}
+
+	refreshTTL, err := time.ParseDuration(conf.Token.RefreshDuration)
+	if err != nil {
+		slog.Error("Error parsing refresh token duration", "error", err)
+		os.Exit(1)
+	}
+
+	loginPolicy, err := config.NewLoginPolicy(conf.Auth)
+	if err != nil {
+		slog.Error("Error parsing login policy", "error", err)
+		os.Exit(1)
+	}
+
+	passwordPolicy, err := config.NewPasswordPolicy(conf.Password)
+	if err != nil {
+		slog.Error("Error parsing password policy", "error", err)
+		os.Exit(1)
+	}
+
+	resetTTL, err := time.ParseDuration(conf.Auth.PasswordResetDuration)
+	if err != nil {
+		slog.Error("Error parsing password reset duration", "error", err)
+		os.Exit(1)
+	}
+
+	verificationTTL, err := time.ParseDuration(conf.Auth.VerificationDuration)
+	if err != nil {
+		slog.Error("Error parsing email verification duration", "error", err)
+		os.Exit(1)
+	}
+
+	resendInterval, err := time.ParseDuration(conf.Auth.VerificationResend)
+	if err != nil {
+		slog.Error("Error parsing email verification resend interval", "error", err)
+		os.Exit(1)
+	}
+
+	outboxPolicy, err := config.NewOutboxPolicy(conf.Events)
+	if err != nil {
+		slog.Error("Error parsing outbox relay settings", "error", err)
+		os.Exit(1)
+	}
+
+	relayInterval, err := time.ParseDuration(conf.Events.RelayInterval)
+	if err != nil {
+		slog.Error("Error parsing outbox relay interval", "error", err)
+		os.Exit(1)
+	}
+
+	cleanupInterval, err := time.ParseDuration(conf.Events.CleanupInterval)
+	if err != nil {
+		slog.Error("Error parsing outbox cleanup interval", "error", err)
+		os.Exit(1)
+	}
+
+	deletedRetention, err := time.ParseDuration(conf.Users.DeletedRetention)
+	if err != nil {
+		slog.Error("Error parsing deleted users retention", "error", err)
+		os.Exit(1)
+	}
+
+	purgeInterval, err := time.ParseDuration(conf.Users.PurgeInterval)
+	if err != nil {
+		slog.Error("Error parsing deleted users purge interval", "error", err)
+		os.Exit(1)
+	}
+
+	purgeEnabled, err := strconv.ParseBool(conf.Users.PurgeEnabled)
+	if err != nil {
+		slog.Error("Error parsing deleted users purge setting", "error", err)
+		os.Exit(1)
+	}
+
+	// Init notifier
+	var notifier port.Notifier
+	switch conf.Notifier.Type {
+	case config.NotifierTypeSMTP:
+		notifier = smtp.New(conf.Notifier)
+	default:
+		notifier = file.New(conf.Notifier)
+	}
+
+	// Init event publisher, the outbox relay marks events as sent once the broker has them
+	events, err := publisherrmq.New(conf.Events)
+	if err != nil {
+		slog.Error("Error initializing event publisher", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := events.Close(); err != nil {
+			slog.Error("Error closing event publisher", "error", err)
+		}
+	}()
+
 	// Dependency injection
+	// Outbox
+	outboxRepo := repository.NewOutboxRepository(db)
+	outboxService := service.NewOutboxService(outboxRepo, events, db, outboxPolicy)
+
 	// User
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo, cache)
+	verificationService := service.NewVerificationService(userRepo, notifier, cache, outboxRepo, db, verificationTTL, resendInterval, conf.Auth.VerificationURL)
 
 	// Auth
-	authService := service.NewAuthService(userRepo, token)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	totpService := totp.New(conf.Auth)
+	authService := service.NewAuthService(userRepo, passwordHasher, token, refreshTokenRepo, mfaRepo, totpService, cache, outboxRepo, db, accessTTL, refreshTTL, loginPolicy)
+
+	// User management, password changes revoke the other sessions of the user
+	userService := service.NewUserService(userRepo, passwordHasher, cache, verificationService, authService, refreshTokenRepo, outboxRepo, db, accessTTL, deletedRetention, passwordPolicy)
+
+	// MFA
+	mfaService := service.NewMFAService(userRepo, mfaRepo, totpService, authService, cache)
+
+	// Password
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	passwordService := service.NewPasswordService(userRepo, passwordHasher, passwordResetRepo, authService, notifier, cache, outboxRepo, db, resetTTL, conf.Auth.PasswordResetURL, passwordPolicy)
+
+	// Role
+	roleRepo := repository.NewRoleRepository(db)
+	authorizer := service.NewAuthorizer(roleRepo, cache)
+	roleService := service.NewRoleService(roleRepo, cache)
 
 	// Config
-	messageService := rmq.New(conf, authService, userService)
+	messageService, err := rmq.New(conf, authService, authorizer, userService, passwordService, verificationService, mfaService, roleService)
+	if err != nil {
+		slog.Error("Error initializing message handler", "error", err)
+		os.Exit(1)
+	}
+
+	// Health check
+	if conf.RMQ.HealthAddr != "" {
+		go serveHealth(conf.RMQ.HealthAddr, messageService)
+	}
+
+	//start consuming until the process is interrupted
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go worker.RunOutboxRelay(ctx, outboxService, outboxPolicy.BatchSize, relayInterval, cleanupInterval)
+	if purgeEnabled {
+		go worker.RunUserPurge(ctx, userService, purgeInterval)
+	}
+
+	err = messageService.Consume(ctx)
+	if err != nil {
+		slog.Error("Error consuming messages", "error", err)
+		os.Exit(1)
+	}
+}
 
-	//start consuming
-	messageService.Consume(ctx)
+// serveHealth serves the state of the connection to the broker for health checks.
+// It responds with 503 Service Unavailable while the handler is not connected
+func serveHealth(addr string, handler *rmq.MessageHandler) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
+		state := handler.State()
+		if state != rmq.StateConnected {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_, _ = w.Write([]byte(state))
+	})
+
+	slog.Info("Serving health check", "addr", addr)
+
+	err := http.ListenAndServe(addr, mux)
+	if err != nil {
+		slog.Error("Error serving health check", "error", err)
+	}
 }
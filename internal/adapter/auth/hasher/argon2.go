@@ -0,0 +1,130 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang-hexagon/internal/core/domain"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// argon2Algorithm is the PHC identifier of the Argon2id algorithm
+	argon2Algorithm = "argon2id"
+	// argon2SaltLength is the length of the random salt in bytes
+	argon2SaltLength = 16
+	// argon2KeyLength is the length of the derived key in bytes
+	argon2KeyLength = 32
+)
+
+// errInvalidHash is an error for when the hash is not in the expected format
+var errInvalidHash = errors.New("invalid password hash format")
+
+// Argon2Params contains the Argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// Argon2Hasher implements port.PasswordHasher interface and hashes passwords
+// with Argon2id in the PHC string format $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type Argon2Hasher struct {
+	params Argon2Params
+}
+
+// NewArgon2Hasher creates a new Argon2id hasher instance
+func NewArgon2Hasher(params Argon2Params) (*Argon2Hasher, error) {
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return nil, errors.New("invalid argon2id parameters")
+	}
+
+	return &Argon2Hasher{
+		params,
+	}, nil
+}
+
+// Hash hashes the password with Argon2id and a random salt
+func (ah *Argon2Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, ah.params.Iterations, ah.params.Memory, ah.params.Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Algorithm,
+		argon2.Version,
+		ah.params.Memory,
+		ah.params.Iterations,
+		ah.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare compares the password with the Argon2id hash using the parameters recorded in the hash
+func (ah *Argon2Hasher) Compare(password, hash string) error {
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return domain.ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether the hash is not an Argon2id hash of the configured parameters
+func (ah *Argon2Hasher) NeedsRehash(hash string) bool {
+	params, _, key, err := decodeArgon2Hash(hash)
+	if err != nil {
+		return true
+	}
+
+	return params != ah.params || len(key) != argon2KeyLength
+}
+
+// decodeArgon2Hash decodes the parameters, salt and key from the Argon2id PHC string
+func decodeArgon2Hash(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	var version int
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != argon2Algorithm {
+		return params, nil, nil, errInvalidHash
+	}
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return params, nil, nil, errInvalidHash
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return params, nil, nil, errInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errInvalidHash
+	}
+
+	return params, salt, key, nil
+}
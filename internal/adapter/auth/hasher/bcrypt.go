@@ -0,0 +1,55 @@
+package hasher
+
+import (
+	"errors"
+	"golang-hexagon/internal/core/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher implements port.PasswordHasher interface
+// and hashes passwords with bcrypt in the $2a$<cost>$ format
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a new bcrypt hasher instance
+func NewBcryptHasher(cost int) (*BcryptHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, errors.New("invalid bcrypt cost")
+	}
+
+	return &BcryptHasher{
+		cost,
+	}, nil
+}
+
+// Hash hashes the password with bcrypt
+func (bh *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bh.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Compare compares the password with the bcrypt hash
+func (bh *BcryptHasher) Compare(password, hash string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// NeedsRehash reports whether the hash is not a bcrypt hash of the configured cost
+func (bh *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+
+	return cost != bh.cost
+}
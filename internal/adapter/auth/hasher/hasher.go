@@ -0,0 +1,101 @@
+package hasher
+
+import (
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"strconv"
+	"strings"
+)
+
+// hashing algorithms
+const (
+	algorithmArgon2id = "argon2id"
+	algorithmBcrypt   = "bcrypt"
+)
+
+// Hasher implements port.PasswordHasher interface.
+// It hashes passwords with the configured algorithm and verifies hashes of every supported algorithm,
+// so that the stored hashes can be upgraded when the algorithm changes
+type Hasher struct {
+	current port.PasswordHasher
+	argon2  *Argon2Hasher
+	bcrypt  *BcryptHasher
+}
+
+// New creates a new password hasher instance from the config
+func New(config *config.Hasher) (port.PasswordHasher, error) {
+	cost, err := strconv.Atoi(config.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	bcryptHasher, err := NewBcryptHasher(cost)
+	if err != nil {
+		return nil, err
+	}
+
+	memory, err := strconv.ParseUint(config.Argon2Memory, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	iterations, err := strconv.ParseUint(config.Argon2Iterations, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	parallelism, err := strconv.ParseUint(config.Argon2Parallelism, 10, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	argon2Hasher, err := NewArgon2Hasher(Argon2Params{
+		Memory:      uint32(memory),
+		Iterations:  uint32(iterations),
+		Parallelism: uint8(parallelism),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var current port.PasswordHasher
+	switch config.Algorithm {
+	case "", algorithmArgon2id:
+		current = argon2Hasher
+	case algorithmBcrypt:
+		current = bcryptHasher
+	default:
+		return nil, domain.ErrHashAlgorithm
+	}
+
+	return &Hasher{
+		current,
+		argon2Hasher,
+		bcryptHasher,
+	}, nil
+}
+
+// Hash hashes the password with the configured algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Compare compares the password with the hash of any supported algorithm
+func (h *Hasher) Compare(password, hash string) error {
+	return h.hasherFor(hash).Compare(password, hash)
+}
+
+// NeedsRehash reports whether the hash uses another algorithm or outdated parameters
+func (h *Hasher) NeedsRehash(hash string) bool {
+	return h.hasherFor(hash) != h.current || h.current.NeedsRehash(hash)
+}
+
+// hasherFor finds the hasher of the algorithm recorded in the hash
+func (h *Hasher) hasherFor(hash string) port.PasswordHasher {
+	if strings.HasPrefix(hash, "$"+argon2Algorithm+"$") {
+		return h.argon2
+	}
+
+	return h.bcrypt
+}
@@ -0,0 +1,274 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const password = "correct horse battery staple"
+
+// argon2Params are cheap Argon2id parameters to keep the tests fast
+var argon2Params = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func newHasher(t *testing.T, algorithm string) port.PasswordHasher {
+	h, err := New(&config.Hasher{
+		Algorithm:         algorithm,
+		BcryptCost:        "4",
+		Argon2Memory:      "64",
+		Argon2Iterations:  "1",
+		Argon2Parallelism: "1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func mustHash(t *testing.T, h port.PasswordHasher) string {
+	hash, err := h.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestDecodeArgon2Hash(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		hash   string
+		params Argon2Params
+		err    error
+	}{
+		{
+			desc:   "Success",
+			hash:   "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U",
+			params: Argon2Params{Memory: 65536, Iterations: 3, Parallelism: 2},
+		},
+		{
+			desc: "Fail_OtherAlgorithm",
+			hash: "$argon2i$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U",
+			err:  errInvalidHash,
+		},
+		{
+			desc: "Fail_OtherVersion",
+			hash: "$argon2id$v=16$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U",
+			err:  errInvalidHash,
+		},
+		{
+			desc: "Fail_Params",
+			hash: "$argon2id$v=19$m=65536,t=3$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U",
+			err:  errInvalidHash,
+		},
+		{
+			desc: "Fail_Salt",
+			hash: "$argon2id$v=19$m=65536,t=3,p=2$not base64!$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U",
+			err:  errInvalidHash,
+		},
+		{
+			desc: "Fail_EmptyKey",
+			hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA$",
+			err:  errInvalidHash,
+		},
+		{
+			desc: "Fail_Parts",
+			hash: "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0c2FsdA",
+			err:  errInvalidHash,
+		},
+		{
+			desc: "Fail_Bcrypt",
+			hash: "$2a$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW",
+			err:  errInvalidHash,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			params, _, _, err := decodeArgon2Hash(tc.hash)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("[case: %s] expected to get %v; got %v", tc.desc, tc.err, err)
+			}
+			if err == nil && params != tc.params {
+				t.Errorf("[case: %s] expected the parameters %+v; got %+v", tc.desc, tc.params, params)
+			}
+		})
+	}
+}
+
+func TestArgon2Hasher_Hash(t *testing.T) {
+	h, err := NewArgon2Hasher(argon2Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := mustHash(t, h)
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("expected a PHC string of the parameters; got %q", hash)
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hash)
+	if err != nil || params != argon2Params || len(salt) != argon2SaltLength || len(key) != argon2KeyLength {
+		t.Errorf("expected to decode the parameters, salt and key of the hash; got %+v, %x, %x, %v", params, salt, key, err)
+	}
+
+	if other := mustHash(t, h); other == hash {
+		t.Errorf("expected a random salt for every hash; got %q twice", hash)
+	}
+}
+
+func TestHasher_Compare(t *testing.T) {
+	argon2Hash := mustHash(t, newHasher(t, algorithmArgon2id))
+	bcryptHash := mustHash(t, newHasher(t, algorithmBcrypt))
+
+	testCases := []struct {
+		desc      string
+		algorithm string
+		password  string
+		hash      string
+		err       error
+	}{
+		{
+			desc:      "Success_Argon2id",
+			algorithm: algorithmArgon2id,
+			password:  password,
+			hash:      argon2Hash,
+		},
+		{
+			desc:      "Success_Bcrypt",
+			algorithm: algorithmBcrypt,
+			password:  password,
+			hash:      bcryptHash,
+		},
+		{
+			desc:      "Success_BcryptHashWithArgon2id",
+			algorithm: algorithmArgon2id,
+			password:  password,
+			hash:      bcryptHash,
+		},
+		{
+			desc:      "Success_Argon2idHashWithBcrypt",
+			algorithm: algorithmBcrypt,
+			password:  password,
+			hash:      argon2Hash,
+		},
+		{
+			desc:      "Fail_WrongPasswordArgon2id",
+			algorithm: algorithmArgon2id,
+			password:  password + "!",
+			hash:      argon2Hash,
+			err:       domain.ErrInvalidCredentials,
+		},
+		{
+			desc:      "Fail_WrongPasswordBcrypt",
+			algorithm: algorithmBcrypt,
+			password:  password + "!",
+			hash:      bcryptHash,
+			err:       domain.ErrInvalidCredentials,
+		},
+		{
+			desc:      "Fail_MalformedHash",
+			algorithm: algorithmArgon2id,
+			password:  password,
+			hash:      "$argon2id$v=19$m=64,t=1,p=1$",
+			err:       domain.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := newHasher(t, tc.algorithm).Compare(tc.password, tc.hash)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	argon2Hash := mustHash(t, newHasher(t, algorithmArgon2id))
+	bcryptHash := mustHash(t, newHasher(t, algorithmBcrypt))
+
+	otherArgon2, err := NewArgon2Hasher(Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherArgon2Hash := mustHash(t, otherArgon2)
+
+	otherBcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc      string
+		algorithm string
+		hash      string
+		rehash    bool
+	}{
+		{
+			desc:      "Argon2id_SameParameters",
+			algorithm: algorithmArgon2id,
+			hash:      argon2Hash,
+			rehash:    false,
+		},
+		{
+			desc:      "Argon2id_OtherParameters",
+			algorithm: algorithmArgon2id,
+			hash:      otherArgon2Hash,
+			rehash:    true,
+		},
+		{
+			desc:      "Argon2id_BcryptHash",
+			algorithm: algorithmArgon2id,
+			hash:      bcryptHash,
+			rehash:    true,
+		},
+		{
+			desc:      "Argon2id_MalformedHash",
+			algorithm: algorithmArgon2id,
+			hash:      "$argon2id$v=19$m=64,t=1,p=1$",
+			rehash:    true,
+		},
+		{
+			desc:      "Bcrypt_SameCost",
+			algorithm: algorithmBcrypt,
+			hash:      bcryptHash,
+			rehash:    false,
+		},
+		{
+			desc:      "Bcrypt_OtherCost",
+			algorithm: algorithmBcrypt,
+			hash:      string(otherBcryptHash),
+			rehash:    true,
+		},
+		{
+			desc:      "Bcrypt_Argon2idHash",
+			algorithm: algorithmBcrypt,
+			hash:      argon2Hash,
+			rehash:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rehash := newHasher(t, tc.algorithm).NeedsRehash(tc.hash)
+			if rehash != tc.rehash {
+				t.Errorf("[case: %s] expected to need a rehash: %t; got %t", tc.desc, tc.rehash, rehash)
+			}
+		})
+	}
+}
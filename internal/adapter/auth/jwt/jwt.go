@@ -0,0 +1,211 @@
+package jwt
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang-hexagon/internal/adapter/auth/keyring"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// JWTToken implements port.TokenService interface
+// and provides an access to the jwt library
+type JWTToken struct {
+	keys     *keyring.Ring[key]
+	method   jwt.SigningMethod
+	parser   *jwt.Parser
+	duration time.Duration
+	issuer   string
+	audience string
+}
+
+// tokenClaims represents the registered jwt claims and the private claims of the token.
+// The additional claims are kept under their own key to not collide with the registered ones
+type tokenClaims struct {
+	Role   domain.UserRole `json:"role"`
+	Claims map[string]any  `json:"ext,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// New creates a new jwt instance
+func New(config *config.Token) (port.TokenService, error) {
+	durationStr := config.Duration
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return nil, domain.ErrTokenDuration
+	}
+
+	var method jwt.SigningMethod
+	switch config.Algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		method = jwt.SigningMethodHS256
+	case jwt.SigningMethodRS256.Alg():
+		method = jwt.SigningMethodRS256
+	case jwt.SigningMethodEdDSA.Alg():
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, domain.ErrTokenAlgorithm
+	}
+
+	keys, err := keyring.New(config, keyParser(method))
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &JWTToken{
+		keys,
+		method,
+		jwt.NewParser(options...),
+		duration,
+		config.Issuer,
+		config.Audience,
+	}, nil
+}
+
+// CreateToken creates a new jwt token
+func (jt *JWTToken) CreateToken(user *domain.User, claims map[string]any) ([]byte, *domain.TokenPayload, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, domain.ErrTokenCreation
+	}
+
+	issuedAt := time.Now().Truncate(time.Second)
+	expiredAt := issuedAt.Add(jt.duration)
+
+	c := tokenClaims{
+		Role:   user.Role,
+		Claims: claims,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.String(),
+			Issuer:    jt.issuer,
+			Subject:   strconv.FormatUint(user.ID, 10),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			NotBefore: jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiredAt),
+		},
+	}
+	if jt.audience != "" {
+		c.Audience = jwt.ClaimStrings{jt.audience}
+	}
+
+	current := jt.keys.Current()
+
+	t := jwt.NewWithClaims(jt.method, c)
+	t.Header["kid"] = current.ID
+
+	token, err := t.SignedString(current.Value.sign)
+	if err != nil {
+		return nil, nil, domain.ErrTokenCreation
+	}
+
+	payload := &domain.TokenPayload{
+		ID:        id,
+		UserID:    user.ID,
+		Role:      user.Role,
+		Issuer:    jt.issuer,
+		Audience:  jt.audience,
+		Claims:    claims,
+		IssuedAt:  issuedAt,
+		ExpiredAt: expiredAt,
+	}
+
+	return []byte(token), payload, nil
+}
+
+// VerifyToken verifies the jwt token
+func (jt *JWTToken) VerifyToken(token []byte) (*domain.TokenPayload, error) {
+	var c tokenClaims
+
+	_, err := jt.parser.ParseWithClaims(string(token), &c, jt.keyFor)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.ErrExpiredToken
+		}
+		return nil, domain.ErrInvalidToken
+	}
+
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidToken
+	}
+
+	var audience string
+	if len(c.Audience) > 0 {
+		audience = c.Audience[0]
+	}
+
+	return &domain.TokenPayload{
+		ID:        id,
+		UserID:    userID,
+		Role:      c.Role,
+		Issuer:    c.Issuer,
+		Audience:  audience,
+		Claims:    c.Claims,
+		IssuedAt:  c.IssuedAt.Time,
+		ExpiredAt: c.ExpiresAt.Time,
+	}, nil
+}
+
+// PublicKeys returns the public keys to verify asymmetrically signed tokens offline
+func (jt *JWTToken) PublicKeys() []domain.PublicKey {
+	var keys []domain.PublicKey
+
+	for _, k := range jt.keys.Active(time.Now()) {
+		if k.Value.public == nil {
+			continue
+		}
+
+		encoded, err := encodePublicKey(k.Value.public)
+		if err != nil {
+			slog.Error("Error encoding public key", "kid", k.ID, "error", err)
+			continue
+		}
+
+		keys = append(keys, domain.PublicKey{
+			ID:        k.ID,
+			Algorithm: jt.method.Alg(),
+			Key:       encoded,
+			ExpiresAt: jt.keys.ExpiresAt(k),
+		})
+	}
+
+	return keys
+}
+
+// keyFor finds the verification key by the key id in the token header.
+// Tokens without the key id are verified with the current key
+func (jt *JWTToken) keyFor(t *jwt.Token) (any, error) {
+	kid, ok := t.Header["kid"].(string)
+	if !ok {
+		return jt.keys.Current().Value.verify, nil
+	}
+
+	k, ok := jt.keys.Lookup(kid, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return k.Value.verify, nil
+}
@@ -0,0 +1,294 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+)
+
+var (
+	hmacKey    = strings.Repeat("01", 32)
+	ed25519Key = strings.Repeat("02", 32)
+	rsaKey     = newRSAKey()
+)
+
+func newRSAKey() string {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func keyFor(algorithm string) string {
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		return rsaKey
+	case jwt.SigningMethodEdDSA.Alg():
+		return ed25519Key
+	default:
+		return hmacKey
+	}
+}
+
+func newTokenService(t *testing.T, algorithm string) *JWTToken {
+	ts, err := New(&config.Token{
+		Duration:  "15m",
+		Algorithm: algorithm,
+		KeyID:     "current",
+		Key:       keyFor(algorithm),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts.(*JWTToken)
+}
+
+// claimsOf returns valid claims of a token of user 1
+func claimsOf() jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ID:        "0b5a3f4e-4d0c-4c3e-9a3e-6f1d8a9c2b7e",
+		Subject:   strconv.Itoa(1),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		algorithm string
+		key       string
+		err       error
+	}{
+		{
+			desc:      "Success_Default",
+			algorithm: "",
+			key:       hmacKey,
+		},
+		{
+			desc:      "Fail_UnknownAlgorithm",
+			algorithm: "none",
+			key:       hmacKey,
+			err:       domain.ErrTokenAlgorithm,
+		},
+		{
+			desc:      "Fail_ShortHMACKey",
+			algorithm: jwt.SigningMethodHS256.Alg(),
+			key:       strings.Repeat("01", 16),
+			err:       domain.ErrTokenKey,
+		},
+		{
+			desc:      "Fail_RSAKeyForEdDSA",
+			algorithm: jwt.SigningMethodEdDSA.Alg(),
+			key:       rsaKey,
+			err:       domain.ErrTokenKey,
+		},
+		{
+			desc:      "Fail_HMACKeyForRS256",
+			algorithm: jwt.SigningMethodRS256.Alg(),
+			key:       hmacKey,
+			err:       domain.ErrTokenKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(&config.Token{
+				Duration:  "15m",
+				Algorithm: tc.algorithm,
+				KeyID:     "current",
+				Key:       tc.key,
+			})
+			if !errors.Is(err, tc.err) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestJWTToken_VerifyToken(t *testing.T) {
+	algorithms := []string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}
+
+	testCases := []struct {
+		desc  string
+		token func(t *testing.T, jt *JWTToken) string
+		err   error
+	}{
+		{
+			desc: "Success_Issued",
+			token: func(t *testing.T, jt *JWTToken) string {
+				token, _, err := jt.CreateToken(&domain.User{ID: 1, Role: domain.Basic}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return string(token)
+			},
+		},
+		{
+			desc: "Fail_OtherAlgorithm",
+			token: func(t *testing.T, jt *JWTToken) string {
+				// a token of the service configured with another algorithm
+				algorithm := jwt.SigningMethodHS256.Alg()
+				if jt.method.Alg() == algorithm {
+					algorithm = jwt.SigningMethodEdDSA.Alg()
+				}
+				token, _, err := newTokenService(t, algorithm).CreateToken(&domain.User{ID: 1, Role: domain.Basic}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return string(token)
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_NoneAlgorithm",
+			token: func(t *testing.T, jt *JWTToken) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodNone, claimsOf()).
+					SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_HMACWithPublicKey",
+			token: func(t *testing.T, jt *JWTToken) string {
+				// the published public key used as the HMAC secret must not verify for asymmetric algorithms
+				secret := []byte("other secret of at least thirty-two bytes")
+				if public := jt.keys.Current().Value.public; public != nil {
+					encoded, err := encodePublicKey(public)
+					if err != nil {
+						t.Fatal(err)
+					}
+					secret = encoded
+				}
+
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claimsOf())
+				token.Header["kid"] = "current"
+				signed, err := token.SignedString(secret)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_UnknownKeyID",
+			token: func(t *testing.T, jt *JWTToken) string {
+				token := jwt.NewWithClaims(jt.method, claimsOf())
+				token.Header["kid"] = "unknown"
+				signed, err := token.SignedString(jt.keys.Current().Value.sign)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_Expired",
+			token: func(t *testing.T, jt *JWTToken) string {
+				claims := claimsOf()
+				claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				token := jwt.NewWithClaims(jt.method, claims)
+				token.Header["kid"] = "current"
+				signed, err := token.SignedString(jt.keys.Current().Value.sign)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			err: domain.ErrExpiredToken,
+		},
+	}
+
+	for _, algorithm := range algorithms {
+		for _, tc := range testCases {
+			tc := tc
+			algorithm := algorithm
+			t.Run(algorithm+"_"+tc.desc, func(t *testing.T) {
+				t.Parallel()
+
+				jt := newTokenService(t, algorithm)
+
+				payload, err := jt.VerifyToken([]byte(tc.token(t, jt)))
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("[case: %s %s] expected to get %v; got %v", algorithm, tc.desc, tc.err, err)
+				}
+				if err == nil && payload.UserID != 1 {
+					t.Errorf("[case: %s %s] expected the payload of user 1; got %+v", algorithm, tc.desc, payload)
+				}
+			})
+		}
+	}
+}
+
+func TestJWTToken_PublicKeys(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		algorithm string
+		keys      int
+	}{
+		{
+			desc:      "HS256",
+			algorithm: jwt.SigningMethodHS256.Alg(),
+			keys:      0,
+		},
+		{
+			desc:      "RS256",
+			algorithm: jwt.SigningMethodRS256.Alg(),
+			keys:      1,
+		},
+		{
+			desc:      "EdDSA",
+			algorithm: jwt.SigningMethodEdDSA.Alg(),
+			keys:      1,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			keys := newTokenService(t, tc.algorithm).PublicKeys()
+			if len(keys) != tc.keys {
+				t.Fatalf("[case: %s] expected %d public keys; got %d", tc.desc, tc.keys, len(keys))
+			}
+			for _, k := range keys {
+				if k.Algorithm != tc.algorithm || k.ID != "current" {
+					t.Errorf("[case: %s] expected the %s key current; got the %s key %s", tc.desc, tc.algorithm, k.Algorithm, k.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"golang-hexagon/internal/adapter/auth/keyring"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minHMACKeySize is the minimal size of the HS256 secret in bytes
+const minHMACKeySize = 32
+
+// errKeyType is an error for when the key material does not match the signing algorithm
+var errKeyType = errors.New("key type does not match the signing algorithm")
+
+// key holds the jwt key material for the signing algorithm.
+// The public key is nil for the symmetric algorithm
+type key struct {
+	sign   any
+	verify any
+	public crypto.PublicKey
+}
+
+// keyParser returns the function that parses the key material for the given signing method
+func keyParser(method jwt.SigningMethod) keyring.ParseFunc[key] {
+	return func(material []byte) (key, error) {
+		switch method {
+		case jwt.SigningMethodHS256:
+			if len(material) < minHMACKeySize {
+				return key{}, errKeyType
+			}
+			return key{
+				sign:   material,
+				verify: material,
+			}, nil
+		case jwt.SigningMethodRS256:
+			privateKey, err := parseRSAPrivateKey(material)
+			if err != nil {
+				return key{}, err
+			}
+			return key{
+				sign:   privateKey,
+				verify: &privateKey.PublicKey,
+				public: &privateKey.PublicKey,
+			}, nil
+		default:
+			privateKey, err := parseEd25519PrivateKey(material)
+			if err != nil {
+				return key{}, err
+			}
+			publicKey := privateKey.Public()
+			return key{
+				sign:   privateKey,
+				verify: publicKey,
+				public: publicKey,
+			}, nil
+		}
+	}
+}
+
+// parseRSAPrivateKey parses the DER encoded PKCS #8 or PKCS #1 RSA private key
+func parseRSAPrivateKey(material []byte) (*rsa.PrivateKey, error) {
+	privateKey, err := x509.ParsePKCS8PrivateKey(material)
+	if err != nil {
+		return x509.ParsePKCS1PrivateKey(material)
+	}
+
+	rsaKey, ok := privateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errKeyType
+	}
+
+	return rsaKey, nil
+}
+
+// parseEd25519PrivateKey parses the 32 bytes seed, the 64 bytes private key
+// or the DER encoded PKCS #8 Ed25519 private key
+func parseEd25519PrivateKey(material []byte) (ed25519.PrivateKey, error) {
+	switch len(material) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(material), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(material), nil
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(material)
+	if err != nil {
+		return nil, err
+	}
+
+	edKey, ok := privateKey.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errKeyType
+	}
+
+	return edKey, nil
+}
+
+// encodePublicKey encodes the public key to publish it,
+// the raw key for Ed25519 and the PKIX, ASN.1 DER form for RSA
+func encodePublicKey(publicKey crypto.PublicKey) ([]byte, error) {
+	edKey, ok := publicKey.(ed25519.PublicKey)
+	if ok {
+		return edKey, nil
+	}
+
+	return x509.MarshalPKIXPublicKey(publicKey)
+}
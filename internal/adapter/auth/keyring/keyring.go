@@ -0,0 +1,246 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// Key is a single entry of the key ring holding the parsed key material
+	Key[K any] struct {
+		ID        string
+		Value     K
+		RetiredAt time.Time
+	}
+
+	// Ring holds the current key used to issue tokens
+	// and the retired keys that are still accepted during the grace period
+	Ring[K any] struct {
+		current *Key[K]
+		keys    map[string]*Key[K]
+		grace   time.Duration
+		parse   ParseFunc[K]
+	}
+
+	// ParseFunc parses the decoded key material into the key of the token adapter
+	ParseFunc[K any] func(material []byte) (K, error)
+
+	// keyFile represents the content of the key ring file
+	keyFile struct {
+		Current string `json:"current"`
+		Keys    []struct {
+			ID        string     `json:"id"`
+			Key       string     `json:"key"`
+			RetiredAt *time.Time `json:"retired_at"`
+		} `json:"keys"`
+	}
+)
+
+// New loads the key ring either from the key file or from the environment variables
+func New[K any](config *config.Token, parse ParseFunc[K]) (*Ring[K], error) {
+	grace := time.Duration(0)
+	if config.GracePeriod != "" {
+		var err error
+		grace, err = time.ParseDuration(config.GracePeriod)
+		if err != nil {
+			return nil, domain.ErrTokenKey
+		}
+	}
+
+	r := &Ring[K]{
+		keys:  make(map[string]*Key[K]),
+		grace: grace,
+		parse: parse,
+	}
+
+	if config.KeyFile != "" {
+		err := r.loadFile(config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err := r.loadEnv(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.current == nil {
+		return nil, domain.ErrTokenKey
+	}
+
+	return r, nil
+}
+
+// Current returns the key used to issue new tokens
+func (r *Ring[K]) Current() *Key[K] {
+	return r.current
+}
+
+// Lookup returns the key by id if it is current or still within the grace period
+func (r *Ring[K]) Lookup(id string, now time.Time) (*Key[K], bool) {
+	k, ok := r.keys[id]
+	if !ok {
+		return nil, false
+	}
+
+	if !k.RetiredAt.IsZero() && now.After(k.RetiredAt.Add(r.grace)) {
+		return nil, false
+	}
+
+	return k, true
+}
+
+// Active returns the keys that are accepted at the moment sorted by id
+func (r *Ring[K]) Active(now time.Time) []*Key[K] {
+	var keys []*Key[K]
+
+	for id := range r.keys {
+		k, ok := r.Lookup(id, now)
+		if ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys
+}
+
+// ExpiresAt returns the end of the grace period of a retired key or nil for the current key
+func (r *Ring[K]) ExpiresAt(k *Key[K]) *time.Time {
+	if k.RetiredAt.IsZero() {
+		return nil
+	}
+
+	expiresAt := k.RetiredAt.Add(r.grace)
+	return &expiresAt
+}
+
+// loadFile loads the key ring from the JSON key file
+func (r *Ring[K]) loadFile(path string) error {
+	var file keyFile
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return domain.ErrTokenKey
+	}
+
+	for _, entry := range file.Keys {
+		var retiredAt time.Time
+		if entry.RetiredAt != nil {
+			retiredAt = *entry.RetiredAt
+		}
+
+		err = r.add(entry.ID, entry.Key, retiredAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	current, ok := r.keys[file.Current]
+	if !ok || !current.RetiredAt.IsZero() {
+		return domain.ErrTokenKey
+	}
+	r.current = current
+
+	return nil
+}
+
+// loadEnv loads the current key and the retired keys from the environment variables.
+// Retired keys are comma separated entries in "id:key:retired_at" format where retired_at is RFC 3339 time
+func (r *Ring[K]) loadEnv(config *config.Token) error {
+	err := r.add(config.KeyID, config.Key, time.Time{})
+	if err != nil {
+		return err
+	}
+	r.current = r.keys[config.KeyID]
+
+	if config.RetiredKeys == "" {
+		return nil
+	}
+
+	for _, entry := range strings.Split(config.RetiredKeys, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 {
+			return domain.ErrTokenKey
+		}
+
+		retiredAt, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil {
+			return domain.ErrTokenKey
+		}
+
+		err = r.add(parts[0], parts[1], retiredAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add decodes and parses the key material and adds the key to the ring
+func (r *Ring[K]) add(id, encoded string, retiredAt time.Time) error {
+	if id == "" {
+		return domain.ErrTokenKey
+	}
+	if _, ok := r.keys[id]; ok {
+		return domain.ErrTokenKey
+	}
+
+	material, err := decodeKey(encoded)
+	if err != nil {
+		return domain.ErrTokenKey
+	}
+
+	value, err := r.parse(material)
+	if err != nil {
+		return domain.ErrTokenKey
+	}
+
+	r.keys[id] = &Key[K]{
+		ID:        id,
+		Value:     value,
+		RetiredAt: retiredAt,
+	}
+
+	return nil
+}
+
+// decodeKey decodes PEM, hex or base64 encoded key material
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	block, _ := pem.Decode([]byte(encoded))
+	if block != nil {
+		return block.Bytes, nil
+	}
+
+	material, err := hex.DecodeString(encoded)
+	if err == nil {
+		return material, nil
+	}
+
+	material, err = base64.StdEncoding.DecodeString(encoded)
+	if err == nil {
+		return material, nil
+	}
+
+	return base64.RawURLEncoding.DecodeString(encoded)
+}
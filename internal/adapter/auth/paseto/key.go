@@ -0,0 +1,75 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"golang-hexagon/internal/adapter/auth/keyring"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// token purposes
+const (
+	purposeLocal  = "local"
+	purposePublic = "public"
+)
+
+// publicKeyAlgorithm is the algorithm of the published public keys
+const publicKeyAlgorithm = "v4.public"
+
+type (
+	// keyFooter is the token footer that identifies the key the token was issued with
+	keyFooter struct {
+		KeyID string `json:"kid"`
+	}
+
+	// key holds the paseto key material.
+	// Depending on the purpose either the symmetric key or the asymmetric key pair is set
+	key struct {
+		symmetric paseto.V4SymmetricKey
+		secret    paseto.V4AsymmetricSecretKey
+		public    paseto.V4AsymmetricPublicKey
+	}
+)
+
+// keyParser returns the function that parses the key material for the given purpose
+func keyParser(purpose paseto.Purpose) keyring.ParseFunc[key] {
+	return func(material []byte) (key, error) {
+		var (
+			k   key
+			err error
+		)
+
+		if purpose == paseto.Public {
+			k.secret, err = newSecretKey(material)
+			if err != nil {
+				return k, err
+			}
+			k.public = k.secret.Public()
+		} else {
+			k.symmetric, err = paseto.V4SymmetricKeyFromBytes(material)
+			if err != nil {
+				return k, err
+			}
+		}
+
+		return k, nil
+	}
+}
+
+// newSecretKey creates an Ed25519 secret key either from the 32 bytes seed or from the 64 bytes secret key
+func newSecretKey(material []byte) (paseto.V4AsymmetricSecretKey, error) {
+	if len(material) == ed25519.SeedSize {
+		return paseto.NewV4AsymmetricSecretKeyFromSeed(hex.EncodeToString(material))
+	}
+
+	return paseto.NewV4AsymmetricSecretKeyFromBytes(material)
+}
+
+// newFooter returns the encoded footer for the key id
+func newFooter(keyID string) []byte {
+	// error is muted here because we know that there will be no encoding errors
+	footer, _ := json.Marshal(keyFooter{KeyID: keyID})
+	return footer
+}
@@ -2,7 +2,9 @@ package paseto
 
 import (
 	"aidanwoods.dev/go-paseto"
+	"encoding/json"
 	"github.com/google/uuid"
+	"golang-hexagon/internal/adapter/auth/keyring"
 	"golang-hexagon/internal/adapter/config"
 	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port"
@@ -12,10 +14,12 @@ import (
 // PasetoToken implements port.TokenService interface
 // and provides an access to the paseto library
 type PasetoToken struct {
-	token    *paseto.Token
-	key      *paseto.V4SymmetricKey
+	keys     *keyring.Ring[key]
+	purpose  paseto.Purpose
 	parser   *paseto.Parser
 	duration time.Duration
+	issuer   string
+	audience string
 }
 
 // New creates a new paseto instance
@@ -26,53 +30,102 @@ func New(config *config.Token) (port.TokenService, error) {
 		return nil, domain.ErrTokenDuration
 	}
 
-	token := paseto.NewToken()
-	key := paseto.NewV4SymmetricKey()
+	var purpose paseto.Purpose
+	switch config.Purpose {
+	case "", purposeLocal:
+		purpose = paseto.Local
+	case purposePublic:
+		purpose = paseto.Public
+	default:
+		return nil, domain.ErrTokenPurpose
+	}
+
+	keys, err := keyring.New(config, keyParser(purpose))
+	if err != nil {
+		return nil, err
+	}
+
 	parser := paseto.NewParser()
+	if config.Issuer != "" {
+		parser.AddRule(paseto.IssuedBy(config.Issuer))
+	}
+	if config.Audience != "" {
+		parser.AddRule(paseto.ForAudience(config.Audience))
+	}
 
 	return &PasetoToken{
-		&token,
-		&key,
+		keys,
+		purpose,
 		&parser,
 		duration,
+		config.Issuer,
+		config.Audience,
 	}, nil
 }
 
 // CreateToken creates a new paseto token
-func (pt *PasetoToken) CreateToken(user *domain.User) ([]byte, error) {
+func (pt *PasetoToken) CreateToken(user *domain.User, claims map[string]any) ([]byte, *domain.TokenPayload, error) {
 	id, err := uuid.NewRandom()
 	if err != nil {
-		return nil, domain.ErrTokenCreation
+		return nil, nil, domain.ErrTokenCreation
 	}
 
+	issuedAt := time.Now().Truncate(time.Second)
+	expiredAt := issuedAt.Add(pt.duration)
+
 	payload := &domain.TokenPayload{
-		ID:     id,
-		UserID: user.ID,
-		Role:   user.Role,
+		ID:        id,
+		UserID:    user.ID,
+		Role:      user.Role,
+		Issuer:    pt.issuer,
+		Audience:  pt.audience,
+		Claims:    claims,
+		IssuedAt:  issuedAt,
+		ExpiredAt: expiredAt,
 	}
 
-	err = pt.token.Set("payload", payload)
+	t := paseto.NewToken()
+
+	err = t.Set("payload", payload)
 	if err != nil {
-		return nil, domain.ErrTokenCreation
+		return nil, nil, domain.ErrTokenCreation
 	}
 
-	issuedAt := time.Now()
-	expiredAt := issuedAt.Add(pt.duration)
+	current := pt.keys.Current()
 
-	pt.token.SetIssuedAt(issuedAt)
-	pt.token.SetNotBefore(issuedAt)
-	pt.token.SetExpiration(expiredAt)
+	if pt.issuer != "" {
+		t.SetIssuer(pt.issuer)
+	}
+	if pt.audience != "" {
+		t.SetAudience(pt.audience)
+	}
+	t.SetIssuedAt(issuedAt)
+	t.SetNotBefore(issuedAt)
+	t.SetExpiration(expiredAt)
+	t.SetFooter(newFooter(current.ID))
 
-	token := []byte(pt.token.V4Encrypt(*pt.key, nil))
+	if pt.purpose == paseto.Public {
+		return []byte(t.V4Sign(current.Value.secret, nil)), payload, nil
+	}
 
-	return token, nil
+	return []byte(t.V4Encrypt(current.Value.symmetric, nil)), payload, nil
 }
 
 // VerifyToken verifies the paseto token
 func (pt *PasetoToken) VerifyToken(token []byte) (*domain.TokenPayload, error) {
 	var payload *domain.TokenPayload
 
-	parsedToken, err := pt.parser.ParseV4Local(*pt.key, string(token), nil)
+	k, err := pt.keyFor(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsedToken *paseto.Token
+	if pt.purpose == paseto.Public {
+		parsedToken, err = pt.parser.ParseV4Public(k.public, string(token), nil)
+	} else {
+		parsedToken, err = pt.parser.ParseV4Local(k.symmetric, string(token), nil)
+	}
 	if err != nil {
 		if err.Error() == "this token has expired" {
 			return nil, domain.ErrExpiredToken
@@ -87,3 +140,55 @@ func (pt *PasetoToken) VerifyToken(token []byte) (*domain.TokenPayload, error) {
 
 	return payload, nil
 }
+
+// PublicKeys returns the public keys to verify v4.public tokens offline
+func (pt *PasetoToken) PublicKeys() []domain.PublicKey {
+	var keys []domain.PublicKey
+
+	if pt.purpose != paseto.Public {
+		return keys
+	}
+
+	for _, k := range pt.keys.Active(time.Now()) {
+		keys = append(keys, domain.PublicKey{
+			ID:        k.ID,
+			Algorithm: publicKeyAlgorithm,
+			Key:       k.Value.public.ExportBytes(),
+			ExpiresAt: pt.keys.ExpiresAt(k),
+		})
+	}
+
+	return keys
+}
+
+// keyFor finds the key the token was issued with by the key id in the token footer.
+// Tokens without a footer are verified with the current key
+func (pt *PasetoToken) keyFor(token []byte) (key, error) {
+	var footer keyFooter
+
+	protocol := paseto.V4Local
+	if pt.purpose == paseto.Public {
+		protocol = paseto.V4Public
+	}
+
+	rawFooter, err := pt.parser.UnsafeParseFooter(protocol, string(token))
+	if err != nil {
+		return key{}, domain.ErrInvalidToken
+	}
+
+	if len(rawFooter) == 0 {
+		return pt.keys.Current().Value, nil
+	}
+
+	err = json.Unmarshal(rawFooter, &footer)
+	if err != nil {
+		return key{}, domain.ErrInvalidToken
+	}
+
+	k, ok := pt.keys.Lookup(footer.KeyID, time.Now())
+	if !ok {
+		return key{}, domain.ErrInvalidToken
+	}
+
+	return k.Value, nil
+}
@@ -0,0 +1,207 @@
+package paseto
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+)
+
+var (
+	oldKey = strings.Repeat("01", 32)
+	newKey = strings.Repeat("02", 32)
+)
+
+func newTokenService(t *testing.T, purpose, keyID, key, retiredKeys string) *PasetoToken {
+	ts, err := New(&config.Token{
+		Duration:    "15m",
+		Purpose:     purpose,
+		KeyID:       keyID,
+		Key:         key,
+		RetiredKeys: retiredKeys,
+		GracePeriod: "24h",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts.(*PasetoToken)
+}
+
+// retiredKey returns the retired key entry of the old key retired the given time ago
+func retiredKey(ago time.Duration) string {
+	return "old:" + oldKey + ":" + time.Now().Add(-ago).Format(time.RFC3339)
+}
+
+// issueWithFooter issues a token with the current key of the service and the raw footer
+func issueWithFooter(pt *PasetoToken, footer []byte) []byte {
+	now := time.Now()
+
+	t := paseto.NewToken()
+	_ = t.Set("payload", &domain.TokenPayload{UserID: 1, IssuedAt: now, ExpiredAt: now.Add(time.Minute)})
+	t.SetIssuedAt(now)
+	t.SetNotBefore(now)
+	t.SetExpiration(now.Add(time.Minute))
+	if footer != nil {
+		t.SetFooter(footer)
+	}
+
+	current := pt.keys.Current().Value
+	if pt.purpose == paseto.Public {
+		return []byte(t.V4Sign(current.secret, nil))
+	}
+	return []byte(t.V4Encrypt(current.symmetric, nil))
+}
+
+func TestPasetoToken_VerifyToken(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		token func(t *testing.T, purpose string) ([]byte, *PasetoToken)
+		err   error
+	}{
+		{
+			desc: "Success_CurrentKey",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				pt := newTokenService(t, purpose, "new", newKey, "")
+				token, _, err := pt.CreateToken(&domain.User{ID: 1, Role: domain.Basic}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token, pt
+			},
+		},
+		{
+			desc: "Success_RotatedKeyWithinGracePeriod",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				issuer := newTokenService(t, purpose, "old", oldKey, "")
+				token, _, err := issuer.CreateToken(&domain.User{ID: 1, Role: domain.Basic}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token, newTokenService(t, purpose, "new", newKey, retiredKey(time.Hour))
+			},
+		},
+		{
+			desc: "Success_NoFooter",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				pt := newTokenService(t, purpose, "new", newKey, "")
+				return issueWithFooter(pt, nil), pt
+			},
+		},
+		{
+			desc: "Fail_RotatedKeyAfterGracePeriod",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				issuer := newTokenService(t, purpose, "old", oldKey, "")
+				token, _, err := issuer.CreateToken(&domain.User{ID: 1, Role: domain.Basic}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token, newTokenService(t, purpose, "new", newKey, retiredKey(25*time.Hour))
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_UnknownKeyID",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				pt := newTokenService(t, purpose, "new", newKey, "")
+				return issueWithFooter(pt, newFooter("unknown")), pt
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_MalformedFooter",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				pt := newTokenService(t, purpose, "new", newKey, "")
+				return issueWithFooter(pt, []byte("new")), pt
+			},
+			err: domain.ErrInvalidToken,
+		},
+		{
+			desc: "Fail_KeyIDOfOtherKey",
+			token: func(t *testing.T, purpose string) ([]byte, *PasetoToken) {
+				issuer := newTokenService(t, purpose, "new", oldKey, "")
+				token, _, err := issuer.CreateToken(&domain.User{ID: 1, Role: domain.Basic}, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token, newTokenService(t, purpose, "new", newKey, "")
+			},
+			err: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, purpose := range []string{purposeLocal, purposePublic} {
+		for _, tc := range testCases {
+			tc := tc
+			purpose := purpose
+			t.Run(purpose+"_"+tc.desc, func(t *testing.T) {
+				t.Parallel()
+
+				token, pt := tc.token(t, purpose)
+
+				payload, err := pt.VerifyToken(token)
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("[case: %s %s] expected to get %v; got %v", purpose, tc.desc, tc.err, err)
+				}
+				if err == nil && payload.UserID != 1 {
+					t.Errorf("[case: %s %s] expected the payload of user 1; got %+v", purpose, tc.desc, payload)
+				}
+			})
+		}
+	}
+}
+
+func TestPasetoToken_PublicKeys(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		purpose string
+		retired string
+		keys    []string
+	}{
+		{
+			desc:    "Local",
+			purpose: purposeLocal,
+			retired: retiredKey(time.Hour),
+			keys:    nil,
+		},
+		{
+			desc:    "PublicWithRotatedKey",
+			purpose: purposePublic,
+			retired: retiredKey(time.Hour),
+			keys:    []string{"new", "old"},
+		},
+		{
+			desc:    "PublicWithExpiredKey",
+			purpose: purposePublic,
+			retired: retiredKey(25 * time.Hour),
+			keys:    []string{"new"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pt := newTokenService(t, tc.purpose, "new", newKey, tc.retired)
+
+			var keys []string
+			for _, k := range pt.PublicKeys() {
+				keys = append(keys, k.ID)
+				if k.Algorithm != publicKeyAlgorithm {
+					t.Errorf("[case: %s] expected the algorithm %q; got %q", tc.desc, publicKeyAlgorithm, k.Algorithm)
+				}
+				if k.ID == "new" && hex.EncodeToString(k.Key) != pt.keys.Current().Value.public.ExportHex() {
+					t.Errorf("[case: %s] expected the public key of the current key; got %x", tc.desc, k.Key)
+				}
+			}
+			if strings.Join(keys, ",") != strings.Join(tc.keys, ",") {
+				t.Errorf("[case: %s] expected the keys %v; got %v", tc.desc, tc.keys, keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package totp
+
+import (
+	"bytes"
+	"golang-hexagon/internal/adapter/config"
+	"image/png"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// qrCodeSize is the width and height of the QR code image in pixels
+const qrCodeSize = 256
+
+// TOTP implements port.TOTPService interface
+// and provides an access to the otp library
+type TOTP struct {
+	issuer string
+}
+
+// New creates a new TOTP service instance
+func New(config *config.Auth) *TOTP {
+	return &TOTP{
+		config.MFAIssuer,
+	}
+}
+
+// GenerateSecret generates a new secret for the account and returns it with its otpauth URI
+func (t *TOTP) GenerateSecret(accountName string) (string, string, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      t.issuer,
+		AccountName: accountName,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// QRCode encodes the otpauth URI as a QR code PNG image
+func (t *TOTP) QRCode(uri string) ([]byte, error) {
+	key, err := otp.NewKeyFromURL(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate checks the code against the secret at the current time, allowing one period of clock skew
+func (t *TOTP) Validate(code, secret string) bool {
+	return totp.Validate(code, secret)
+}
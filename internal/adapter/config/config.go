@@ -15,15 +15,38 @@ const (
 	appTypeHTTP   = "http"
 )
 
+// token types
+const (
+	TokenTypePaseto = "paseto"
+	TokenTypeJWT    = "jwt"
+)
+
+// notifier types
+const (
+	NotifierTypeFile = "file"
+	NotifierTypeSMTP = "smtp"
+)
+
+// event publisher types
+const (
+	EventsTypeRMQ = "rmq"
+)
+
 type (
-	// Container contains environment variables for the application, database, cache, token, and http server
+	// Container contains environment variables for the application, database, cache, token, auth, hasher, password policy, notifier, event publisher, users, and http server
 	Container struct {
-		App   *App
-		Redis *Redis
-		DB    *DB
-		Token *Token
-		RMQ   *rmq.Config
-		HTTP  *http.Config
+		App      *App
+		Redis    *Redis
+		DB       *DB
+		Token    *Token
+		Auth     *Auth
+		Hasher   *Hasher
+		Password *PasswordPolicy
+		Notifier *Notifier
+		Events   *Events
+		Users    *Users
+		RMQ      *rmq.Config
+		HTTP     *http.Config
 	}
 
 	// App contains all the environment variables for the application
@@ -40,7 +63,86 @@ type (
 
 	// Token contains all the environment variables for the token service
 	Token struct {
-		Duration string
+		Type            string
+		Duration        string
+		RefreshDuration string
+		Purpose         string
+		Algorithm       string
+		Issuer          string
+		Audience        string
+		KeyID           string
+		Key             string
+		KeyFile         string
+		RetiredKeys     string
+		GracePeriod     string
+	}
+
+	// Auth contains all the environment variables for the login protection
+	Auth struct {
+		MaxLoginAttempts       string
+		MaxSourceLoginAttempts string
+		LockoutDuration        string
+		LoginDelay             string
+		PasswordResetDuration  string
+		PasswordResetURL       string
+		RequireVerifiedEmail   string
+		VerificationDuration   string
+		VerificationResend     string
+		VerificationURL        string
+		MFAIssuer              string
+		MFAChallengeDuration   string
+		MFARequiredForAdmins   string
+	}
+
+	// Hasher contains all the environment variables for the password hashing
+	Hasher struct {
+		Algorithm         string
+		BcryptCost        string
+		Argon2Memory      string
+		Argon2Iterations  string
+		Argon2Parallelism string
+	}
+
+	// PasswordPolicy contains all the environment variables for the password policy
+	PasswordPolicy struct {
+		MinLength     string
+		MaxLength     string
+		RequireUpper  string
+		RequireLower  string
+		RequireDigit  string
+		RequireSymbol string
+		DenylistFile  string
+	}
+
+	// Notifier contains all the environment variables for the notification service
+	Notifier struct {
+		Type         string
+		From         string
+		SMTPHost     string
+		SMTPPort     string
+		SMTPUser     string
+		SMTPPassword string
+		File         string
+	}
+
+	// Events contains all the environment variables for the event publisher and the outbox relay
+	Events struct {
+		Type            string
+		URL             string
+		Exchange        string
+		RelayInterval   string
+		RelayBatchSize  string
+		RetryDelay      string
+		MaxRetryDelay   string
+		Retention       string
+		CleanupInterval string
+	}
+
+	// Users contains all the environment variables for the retention of deleted users
+	Users struct {
+		DeletedRetention string
+		PurgeInterval    string
+		PurgeEnabled     string
 	}
 
 	// DB contains all the environment variables for the database
@@ -75,7 +177,80 @@ func New() (*Container, error) {
 	}
 
 	token := &Token{
-		Duration: os.Getenv("TOKEN_DURATION"),
+		Type:            os.Getenv("TOKEN_TYPE"),
+		Duration:        os.Getenv("TOKEN_DURATION"),
+		RefreshDuration: os.Getenv("TOKEN_REFRESH_DURATION"),
+		Purpose:         os.Getenv("TOKEN_PURPOSE"),
+		Algorithm:       os.Getenv("TOKEN_ALGORITHM"),
+		Issuer:          os.Getenv("TOKEN_ISSUER"),
+		Audience:        os.Getenv("TOKEN_AUDIENCE"),
+		KeyID:           os.Getenv("TOKEN_KEY_ID"),
+		Key:             os.Getenv("TOKEN_KEY"),
+		KeyFile:         os.Getenv("TOKEN_KEY_FILE"),
+		RetiredKeys:     os.Getenv("TOKEN_RETIRED_KEYS"),
+		GracePeriod:     os.Getenv("TOKEN_KEY_GRACE_PERIOD"),
+	}
+
+	auth := &Auth{
+		MaxLoginAttempts:       os.Getenv("AUTH_MAX_LOGIN_ATTEMPTS"),
+		MaxSourceLoginAttempts: os.Getenv("AUTH_MAX_SOURCE_LOGIN_ATTEMPTS"),
+		LockoutDuration:        os.Getenv("AUTH_LOCKOUT_DURATION"),
+		LoginDelay:             os.Getenv("AUTH_LOGIN_DELAY"),
+		PasswordResetDuration:  os.Getenv("AUTH_PASSWORD_RESET_DURATION"),
+		PasswordResetURL:       os.Getenv("AUTH_PASSWORD_RESET_URL"),
+		RequireVerifiedEmail:   os.Getenv("AUTH_REQUIRE_VERIFIED_EMAIL"),
+		VerificationDuration:   os.Getenv("AUTH_VERIFICATION_DURATION"),
+		VerificationResend:     os.Getenv("AUTH_VERIFICATION_RESEND_INTERVAL"),
+		VerificationURL:        os.Getenv("AUTH_VERIFICATION_URL"),
+		MFAIssuer:              os.Getenv("AUTH_MFA_ISSUER"),
+		MFAChallengeDuration:   os.Getenv("AUTH_MFA_CHALLENGE_DURATION"),
+		MFARequiredForAdmins:   os.Getenv("AUTH_MFA_REQUIRED_FOR_ADMINS"),
+	}
+
+	hasher := &Hasher{
+		Algorithm:         os.Getenv("PASSWORD_HASH_ALGORITHM"),
+		BcryptCost:        os.Getenv("PASSWORD_BCRYPT_COST"),
+		Argon2Memory:      os.Getenv("PASSWORD_ARGON2_MEMORY"),
+		Argon2Iterations:  os.Getenv("PASSWORD_ARGON2_ITERATIONS"),
+		Argon2Parallelism: os.Getenv("PASSWORD_ARGON2_PARALLELISM"),
+	}
+
+	password := &PasswordPolicy{
+		MinLength:     os.Getenv("PASSWORD_MIN_LENGTH"),
+		MaxLength:     os.Getenv("PASSWORD_MAX_LENGTH"),
+		RequireUpper:  os.Getenv("PASSWORD_REQUIRE_UPPER"),
+		RequireLower:  os.Getenv("PASSWORD_REQUIRE_LOWER"),
+		RequireDigit:  os.Getenv("PASSWORD_REQUIRE_DIGIT"),
+		RequireSymbol: os.Getenv("PASSWORD_REQUIRE_SYMBOL"),
+		DenylistFile:  os.Getenv("PASSWORD_DENYLIST_FILE"),
+	}
+
+	notifier := &Notifier{
+		Type:         os.Getenv("NOTIFIER_TYPE"),
+		From:         os.Getenv("NOTIFIER_FROM"),
+		SMTPHost:     os.Getenv("NOTIFIER_SMTP_HOST"),
+		SMTPPort:     os.Getenv("NOTIFIER_SMTP_PORT"),
+		SMTPUser:     os.Getenv("NOTIFIER_SMTP_USER"),
+		SMTPPassword: os.Getenv("NOTIFIER_SMTP_PASSWORD"),
+		File:         os.Getenv("NOTIFIER_FILE"),
+	}
+
+	events := &Events{
+		Type:            os.Getenv("EVENTS_TYPE"),
+		URL:             os.Getenv("EVENTS_RMQ_URL"),
+		Exchange:        os.Getenv("EVENTS_EXCHANGE"),
+		RelayInterval:   os.Getenv("EVENTS_RELAY_INTERVAL"),
+		RelayBatchSize:  os.Getenv("EVENTS_RELAY_BATCH_SIZE"),
+		RetryDelay:      os.Getenv("EVENTS_RELAY_RETRY_DELAY"),
+		MaxRetryDelay:   os.Getenv("EVENTS_RELAY_MAX_RETRY_DELAY"),
+		Retention:       os.Getenv("EVENTS_OUTBOX_RETENTION"),
+		CleanupInterval: os.Getenv("EVENTS_OUTBOX_CLEANUP_INTERVAL"),
+	}
+
+	users := &Users{
+		DeletedRetention: os.Getenv("USERS_DELETED_RETENTION"),
+		PurgeInterval:    os.Getenv("USERS_PURGE_INTERVAL"),
+		PurgeEnabled:     os.Getenv("USERS_PURGE_ENABLED"),
 	}
 
 	db := &DB{
@@ -88,10 +263,35 @@ func New() (*Container, error) {
 	}
 
 	container := &Container{
-		App:   app,
-		Redis: redis,
-		DB:    db,
-		Token: token,
+		App:      app,
+		Redis:    redis,
+		DB:       db,
+		Token:    token,
+		Auth:     auth,
+		Hasher:   hasher,
+		Password: password,
+		Notifier: notifier,
+		Events:   events,
+		Users:    users,
+	}
+
+	switch token.Type {
+	case "", TokenTypePaseto, TokenTypeJWT:
+	default:
+		return nil, fmt.Errorf("invalid token type: %s", token.Type)
+	}
+
+	switch notifier.Type {
+	case "", NotifierTypeFile, NotifierTypeSMTP:
+	default:
+		return nil, fmt.Errorf("invalid notifier type: %s", notifier.Type)
+	}
+
+	// events are only marked as sent once a broker has them, so there is no publisher that does not need one
+	switch events.Type {
+	case "", EventsTypeRMQ:
+	default:
+		return nil, fmt.Errorf("invalid event publisher type: %s", events.Type)
 	}
 
 	switch app.Type {
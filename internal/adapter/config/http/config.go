@@ -14,6 +14,8 @@ type Config struct {
 	URL            string
 	Port           string
 	AllowedOrigins string
+	RequireIfMatch string
+	TrustedProxies string
 }
 
 // New creates a new container instance
@@ -30,5 +32,7 @@ func New() (*Config, error) {
 		URL:            os.Getenv("HTTP_URL"),
 		Port:           os.Getenv("HTTP_PORT"),
 		AllowedOrigins: os.Getenv("HTTP_ALLOWED_ORIGINS"),
+		RequireIfMatch: os.Getenv("HTTP_REQUIRE_IF_MATCH"),
+		TrustedProxies: os.Getenv("HTTP_TRUSTED_PROXIES"),
 	}, nil
 }
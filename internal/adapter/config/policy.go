@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bufio"
+	"golang-hexagon/internal/core/service"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewLoginPolicy parses the login protection settings
+func NewLoginPolicy(conf *Auth) (service.LoginPolicy, error) {
+	var policy service.LoginPolicy
+
+	maxAttempts, err := strconv.ParseInt(conf.MaxLoginAttempts, 10, 64)
+	if err != nil {
+		return policy, err
+	}
+
+	sourceMaxAttempts, err := strconv.ParseInt(conf.MaxSourceLoginAttempts, 10, 64)
+	if err != nil {
+		return policy, err
+	}
+
+	lockoutDuration, err := time.ParseDuration(conf.LockoutDuration)
+	if err != nil {
+		return policy, err
+	}
+
+	delay, err := time.ParseDuration(conf.LoginDelay)
+	if err != nil {
+		return policy, err
+	}
+
+	requireVerifiedEmail, err := strconv.ParseBool(conf.RequireVerifiedEmail)
+	if err != nil {
+		return policy, err
+	}
+
+	mfaChallengeDuration, err := time.ParseDuration(conf.MFAChallengeDuration)
+	if err != nil {
+		return policy, err
+	}
+
+	return service.LoginPolicy{
+		MaxAttempts:          maxAttempts,
+		SourceMaxAttempts:    sourceMaxAttempts,
+		LockoutDuration:      lockoutDuration,
+		Delay:                delay,
+		RequireVerifiedEmail: requireVerifiedEmail,
+		MFAChallengeDuration: mfaChallengeDuration,
+	}, nil
+}
+
+// NewPasswordPolicy parses the password policy settings and loads the password denylist
+func NewPasswordPolicy(conf *PasswordPolicy) (service.PasswordPolicy, error) {
+	var policy service.PasswordPolicy
+
+	minLength, err := strconv.Atoi(conf.MinLength)
+	if err != nil {
+		return policy, err
+	}
+
+	maxLength, err := strconv.Atoi(conf.MaxLength)
+	if err != nil {
+		return policy, err
+	}
+
+	requireUpper, err := strconv.ParseBool(conf.RequireUpper)
+	if err != nil {
+		return policy, err
+	}
+
+	requireLower, err := strconv.ParseBool(conf.RequireLower)
+	if err != nil {
+		return policy, err
+	}
+
+	requireDigit, err := strconv.ParseBool(conf.RequireDigit)
+	if err != nil {
+		return policy, err
+	}
+
+	requireSymbol, err := strconv.ParseBool(conf.RequireSymbol)
+	if err != nil {
+		return policy, err
+	}
+
+	denylist := make(map[string]struct{})
+	if conf.DenylistFile != "" {
+		file, err := os.Open(conf.DenylistFile)
+		if err != nil {
+			return policy, err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			password := strings.TrimSpace(scanner.Text())
+			if password != "" {
+				denylist[strings.ToLower(password)] = struct{}{}
+			}
+		}
+
+		err = scanner.Err()
+		if err != nil {
+			return policy, err
+		}
+	}
+
+	return service.PasswordPolicy{
+		MinLength:     minLength,
+		MaxLength:     maxLength,
+		RequireUpper:  requireUpper,
+		RequireLower:  requireLower,
+		RequireDigit:  requireDigit,
+		RequireSymbol: requireSymbol,
+		Denylist:      denylist,
+	}, nil
+}
+
+// NewOutboxPolicy parses the outbox relay settings
+func NewOutboxPolicy(conf *Events) (service.OutboxPolicy, error) {
+	var policy service.OutboxPolicy
+
+	batchSize, err := strconv.ParseUint(conf.RelayBatchSize, 10, 64)
+	if err != nil {
+		return policy, err
+	}
+
+	retryDelay, err := time.ParseDuration(conf.RetryDelay)
+	if err != nil {
+		return policy, err
+	}
+
+	maxRetryDelay, err := time.ParseDuration(conf.MaxRetryDelay)
+	if err != nil {
+		return policy, err
+	}
+
+	retention, err := time.ParseDuration(conf.Retention)
+	if err != nil {
+		return policy, err
+	}
+
+	return service.OutboxPolicy{
+		BatchSize:     batchSize,
+		RetryDelay:    retryDelay,
+		MaxRetryDelay: maxRetryDelay,
+		Retention:     retention,
+	}, nil
+}
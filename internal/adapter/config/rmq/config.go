@@ -10,15 +10,30 @@ const EnvProduction = "production"
 
 // Config contains all the environment variables for the RabbitMQ
 type Config struct {
-	Host          string
-	Port          string
-	User          string
-	Password      string
-	Vhost         string
-	InQueue       string
-	ConsumerTag   string
-	OutExchange   string
-	OutRoutingKey string
+	Host               string
+	Port               string
+	User               string
+	Password           string
+	Vhost              string
+	InQueue            string
+	ConsumerTag        string
+	OutExchange        string
+	OutRoutingKey      string
+	Prefetch           string
+	Workers            string
+	MessageTimeout     string
+	ReconnectMinDelay  string
+	ReconnectMaxDelay  string
+	HealthAddr         string
+	MaxAttempts        string
+	RetryExchange      string
+	RetryQueue         string
+	RetryDelay         string
+	DeadLetterExchange string
+	DeadLetterQueue    string
+	TopologyFile       string
+	TopologyPassive    string
+	RequireVersion     string
 }
 
 // New creates a new container instance
@@ -31,14 +46,29 @@ func New() (*Config, error) {
 	}
 
 	return &Config{
-		Host:          os.Getenv("RMQ_HOST"),
-		Port:          os.Getenv("RMQ_PORT"),
-		User:          os.Getenv("RMQ_USER"),
-		Password:      os.Getenv("RMQ_PASSWORD"),
-		Vhost:         os.Getenv("RMQ_VHOST"),
-		InQueue:       os.Getenv("RMQ_IN_QUEUE"),
-		ConsumerTag:   os.Getenv("RMQ_CONSUMER_TAG"),
-		OutExchange:   os.Getenv("RMQ_OUT_EXCHANGE"),
-		OutRoutingKey: os.Getenv("RMQ_OUT_QUEUE"),
+		Host:               os.Getenv("RMQ_HOST"),
+		Port:               os.Getenv("RMQ_PORT"),
+		User:               os.Getenv("RMQ_USER"),
+		Password:           os.Getenv("RMQ_PASSWORD"),
+		Vhost:              os.Getenv("RMQ_VHOST"),
+		InQueue:            os.Getenv("RMQ_IN_QUEUE"),
+		ConsumerTag:        os.Getenv("RMQ_CONSUMER_TAG"),
+		OutExchange:        os.Getenv("RMQ_OUT_EXCHANGE"),
+		OutRoutingKey:      os.Getenv("RMQ_OUT_QUEUE"),
+		Prefetch:           os.Getenv("RMQ_PREFETCH"),
+		Workers:            os.Getenv("RMQ_WORKERS"),
+		MessageTimeout:     os.Getenv("RMQ_MESSAGE_TIMEOUT"),
+		ReconnectMinDelay:  os.Getenv("RMQ_RECONNECT_MIN_DELAY"),
+		ReconnectMaxDelay:  os.Getenv("RMQ_RECONNECT_MAX_DELAY"),
+		HealthAddr:         os.Getenv("RMQ_HEALTH_ADDR"),
+		MaxAttempts:        os.Getenv("RMQ_MAX_ATTEMPTS"),
+		RetryExchange:      os.Getenv("RMQ_RETRY_EXCHANGE"),
+		RetryQueue:         os.Getenv("RMQ_RETRY_QUEUE"),
+		RetryDelay:         os.Getenv("RMQ_RETRY_DELAY"),
+		DeadLetterExchange: os.Getenv("RMQ_DEAD_LETTER_EXCHANGE"),
+		DeadLetterQueue:    os.Getenv("RMQ_DEAD_LETTER_QUEUE"),
+		TopologyFile:       os.Getenv("RMQ_TOPOLOGY_FILE"),
+		TopologyPassive:    os.Getenv("RMQ_TOPOLOGY_PASSIVE"),
+		RequireVersion:     os.Getenv("RMQ_REQUIRE_VERSION"),
 	}, nil
 }
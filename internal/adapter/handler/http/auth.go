@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"golang-hexagon/internal/core/port"
+	"io"
 )
 
 // AuthHandler represents the HTTP handler for authentication-related requests
@@ -20,21 +22,24 @@ func NewAuthHandler(svc port.AuthService) *AuthHandler {
 // loginRequest represents the request body for logging in a user
 type loginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"test@example.com"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678" minLength:"8"`
+	Password string `json:"password" binding:"required" example:"Passw0rd"`
 }
 
 // Login godoc
 //
 //	@Summary		Login and get an access token
-//	@Description	Logs in a registered user and returns an access token if the credentials are valid.
+//	@Description	Logs in a registered user and returns an access and refresh token pair with their expiry times if the credentials are valid. Users with enabled MFA get an MFA challenge token instead, to be exchanged for the token pair with a TOTP or recovery code. Failed attempts delay the next attempt, and too many of them temporarily lock the account or block the client.
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		loginRequest	true	"Login request body"
-//	@Success		200		{object}	authResponse	"Succesfully logged in"
-//	@Failure		400		{object}	errorResponse	"Validation error"
-//	@Failure		401		{object}	errorResponse	"Unauthorized error"
-//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Param			request	body		loginRequest			true	"Login request body"
+//	@Success		200		{object}	authResponse			"Succesfully logged in"
+//	@Success		202		{object}	mfaChallengeResponse	"Second factor required"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		423		{object}	errorResponse			"Account locked error"
+//	@Failure		429		{object}	errorResponse			"Too many login attempts error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
 //	@Router			/users/login [post]
 func (ah *AuthHandler) Login(ctx *gin.Context) {
 	var req loginRequest
@@ -43,13 +48,206 @@ func (ah *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := ah.svc.Login(ctx, req.Email, req.Password)
+	tokens, challenge, err := ah.svc.Login(ctx, req.Email, req.Password, ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if challenge != nil {
+		rsp := newMFAChallengeResponse(challenge)
+
+		handleAccepted(ctx, rsp)
+		return
+	}
+
+	rsp := newAuthResponse(tokens)
+
+	handleSuccess(ctx, rsp)
+}
+
+// verifyMFARequest represents the request body for completing the login with the second factor
+type verifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required" example:"Yk1p3Cq0n9wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+// VerifyMFA godoc
+//
+//	@Summary		Complete the login with the second factor
+//	@Description	Exchanges the MFA challenge token from the login and a TOTP code or an unused recovery code for an access and refresh token pair. Invalid codes count as failed login attempts.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyMFARequest	true	"MFA login request body"
+//	@Success		200		{object}	authResponse		"Succesfully logged in"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		401		{object}	errorResponse		"Unauthorized error"
+//	@Failure		423		{object}	errorResponse		"Account locked error"
+//	@Failure		429		{object}	errorResponse		"Too many login attempts error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/users/login/mfa [post]
+func (ah *AuthHandler) VerifyMFA(ctx *gin.Context) {
+	var req verifyMFARequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	tokens, err := ah.svc.VerifyMFA(ctx, []byte(req.MFAToken), req.Code, ctx.ClientIP())
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	rsp := newAuthResponse(string(token))
+	rsp := newAuthResponse(tokens)
 
 	handleSuccess(ctx, rsp)
 }
+
+// refreshRequest represents the request body for refreshing an access token
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"q8F3dPZ0n1wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+}
+
+// Refresh godoc
+//
+//	@Summary		Refresh an access token
+//	@Description	Exchanges a refresh token for a new access and refresh token pair. The used refresh token is rotated and can not be used again.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		refreshRequest	true	"Refresh request body"
+//	@Success		200		{object}	authResponse	"Succesfully refreshed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/users/refresh [post]
+func (ah *AuthHandler) Refresh(ctx *gin.Context) {
+	var req refreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	tokens, err := ah.svc.Refresh(ctx, []byte(req.RefreshToken))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newAuthResponse(tokens)
+
+	handleSuccess(ctx, rsp)
+}
+
+// logoutRequest represents the request body for logging out a user
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"q8F3dPZ0n1wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+}
+
+// Logout godoc
+//
+//	@Summary		Logout and revoke the access token
+//	@Description	Revokes the access token of the request. If the refresh token is given, its whole token family is revoked as well.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		logoutRequest	false	"Logout request body"
+//	@Success		200		{object}	response		"Succesfully logged out"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/users/logout [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) Logout(ctx *gin.Context) {
+	var req logoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := ah.svc.Logout(ctx, payload, []byte(req.RefreshToken))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// revokeUserTokensRequest represents the request body for revoking the tokens of a user
+type revokeUserTokensRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// RevokeUserTokens godoc
+//
+//	@Summary		Revoke all tokens of a user
+//	@Description	Revokes every access and refresh token issued to the user so far
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"User ID"
+//	@Success		200	{object}	response		"Tokens revoked"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/{id}/revoke [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) RevokeUserTokens(ctx *gin.Context) {
+	var req revokeUserTokensRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ah.svc.RevokeUserTokens(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// unlockUserRequest represents the request body for unlocking a user account
+type unlockUserRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// UnlockUser godoc
+//
+//	@Summary		Unlock a user account
+//	@Description	Unlocks the user account locked after too many failed login attempts before the lockout expires
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"User ID"
+//	@Success		200	{object}	response		"Account unlocked"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/{id}/unlock [post]
+//	@Security		BearerAuth
+func (ah *AuthHandler) UnlockUser(ctx *gin.Context) {
+	var req unlockUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ah.svc.UnlockUser(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
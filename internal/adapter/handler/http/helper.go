@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"golang-hexagon/internal/core/domain"
 	"strconv"
+	"strings"
 )
 
 // getAuthPayload is a helper function to get the auth payload from the context
@@ -25,3 +26,67 @@ func stringToUint64(str string) (uint64, error) {
 
 	return num, err
 }
+
+// setETag is a helper function to set the version of the user as a strong entity tag in the ETag header
+func setETag(ctx *gin.Context, user *domain.User) {
+	ctx.Header("ETag", strconv.Quote(strconv.FormatUint(user.Version, 10)))
+}
+
+// ifMatchVersion is a helper function to get the version of the user from the If-Match header.
+// It returns 0 if the header is missing and not required, or is "*" to match any version.
+// A tag that is not a version of the user can never match, so it is reported as a version mismatch
+func ifMatchVersion(ctx *gin.Context, required bool) (uint64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		if required {
+			return 0, domain.ErrVersionRequired
+		}
+		return 0, nil
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, domain.ErrVersionMismatch
+	}
+
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || version == 0 {
+		return 0, domain.ErrVersionMismatch
+	}
+
+	return version, nil
+}
+
+// toPermissions is a helper function to parse permissions in the "resource:action" format.
+// It keeps nil permissions nil, so that they are not replaced on update
+func toPermissions(permissions []string) ([]domain.Permission, error) {
+	if permissions == nil {
+		return nil, nil
+	}
+
+	parsed := make([]domain.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		p, err := domain.ParsePermission(permission)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, p)
+	}
+
+	return parsed, nil
+}
+
+// newUserProfileResponse is a helper function to create the response body of a user
+// that is projected to the public profile unless it is the user's own or the account details may be read
+func newUserProfileResponse(user *domain.User, payload *domain.TokenPayload, readDetails bool) any {
+	if readDetails || user.ID == payload.UserID {
+		return newUserResponse(user)
+	}
+
+	return newPublicUserResponse(user)
+}
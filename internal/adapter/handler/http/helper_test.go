@@ -0,0 +1,97 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang-hexagon/internal/core/domain"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		desc     string
+		header   string
+		required bool
+		version  uint64
+		err      error
+	}{
+		{
+			desc:    "Success_Missing",
+			header:  "",
+			version: 0,
+		},
+		{
+			desc:     "Fail_MissingRequired",
+			header:   "",
+			required: true,
+			err:      domain.ErrVersionRequired,
+		},
+		{
+			desc:     "Success_Any",
+			header:   "*",
+			required: true,
+			version:  0,
+		},
+		{
+			desc:    "Success_Quoted",
+			header:  `"3"`,
+			version: 3,
+		},
+		{
+			desc:    "Success_QuotedWithSpaces",
+			header:  ` "42" `,
+			version: 42,
+		},
+		{
+			desc:   "Fail_Weak",
+			header: `W/"3"`,
+			err:    domain.ErrVersionMismatch,
+		},
+		{
+			desc:   "Fail_Unquoted",
+			header: "3",
+			err:    domain.ErrVersionMismatch,
+		},
+		{
+			desc:   "Fail_NotVersion",
+			header: `"abc"`,
+			err:    domain.ErrVersionMismatch,
+		},
+		{
+			desc:   "Fail_ZeroVersion",
+			header: `"0"`,
+			err:    domain.ErrVersionMismatch,
+		},
+		{
+			desc:   "Fail_List",
+			header: `"3", "4"`,
+			err:    domain.ErrVersionMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/users/1", nil)
+			if tc.header != "" {
+				ctx.Request.Header.Set("If-Match", tc.header)
+			}
+
+			version, err := ifMatchVersion(ctx, tc.required)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.err, err)
+			}
+			if version != tc.version {
+				t.Errorf("[case: %s] expected to get version %d; got %d", tc.desc, tc.version, version)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-hexagon/internal/core/port"
+)
+
+// keysCacheControl lets the verifying services cache the public keys for a while
+const keysCacheControl = "public, max-age=300"
+
+// KeyHandler represents the HTTP handler for token key-related requests
+type KeyHandler struct {
+	ts port.TokenService
+}
+
+// NewKeyHandler creates a new KeyHandler instance
+func NewKeyHandler(ts port.TokenService) *KeyHandler {
+	return &KeyHandler{
+		ts,
+	}
+}
+
+// PublicKeys godoc
+//
+//	@Summary		List token public keys
+//	@Description	Lists the public keys with their key IDs to verify v4.public, RS256 and EdDSA tokens offline. Retired keys are listed until the end of their grace period. The list is empty for v4.local and HS256 tokens.
+//	@Tags			Keys
+//	@Produce		json
+//	@Success		200	{object}	publicKeysResponse	"Public keys displayed"
+//	@Router			/.well-known/token-keys [get]
+func (kh *KeyHandler) PublicKeys(ctx *gin.Context) {
+	keys := kh.ts.PublicKeys()
+
+	rsp := newPublicKeysResponse(keys)
+
+	ctx.Header("Cache-Control", keysCacheControl)
+	handleSuccess(ctx, rsp)
+}
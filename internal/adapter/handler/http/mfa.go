@@ -0,0 +1,150 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-hexagon/internal/core/port"
+)
+
+// MFAHandler represents the HTTP handler for multi-factor authentication-related requests
+type MFAHandler struct {
+	svc port.MFAService
+}
+
+// NewMFAHandler creates a new MFAHandler instance
+func NewMFAHandler(svc port.MFAService) *MFAHandler {
+	return &MFAHandler{
+		svc,
+	}
+}
+
+// mfaCodeRequest represents the request body with a TOTP or recovery code
+type mfaCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// EnrollTOTP godoc
+//
+//	@Summary		Enroll a TOTP authenticator
+//	@Description	Creates a pending TOTP authenticator of the current user and returns its secret, otpauth URI and QR code PNG image to add it to an authenticator app. The authenticator is used on login after it is confirmed.
+//	@Tags			MFA
+//	@Produce		json
+//	@Success		200	{object}	totpEnrollmentResponse	"TOTP authenticator enrolled"
+//	@Failure		401	{object}	errorResponse			"Unauthorized error"
+//	@Failure		409	{object}	errorResponse			"MFA already enabled error"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/me/mfa/totp [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) EnrollTOTP(ctx *gin.Context) {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	enrollment, err := mh.svc.EnrollTOTP(ctx, payload.UserID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newTOTPEnrollmentResponse(enrollment)
+
+	handleSuccess(ctx, rsp)
+}
+
+// ConfirmTOTP godoc
+//
+//	@Summary		Confirm the TOTP authenticator
+//	@Description	Enables the pending TOTP authenticator of the current user with a code from the authenticator app and returns one-time recovery codes. All sessions of the user are revoked and the next logins require the second factor.
+//	@Tags			MFA
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest			true	"TOTP code request body"
+//	@Success		200		{object}	recoveryCodesResponse	"MFA enabled"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		409		{object}	errorResponse			"MFA already enabled error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/me/mfa/totp/confirm [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) ConfirmTOTP(ctx *gin.Context) {
+	var req mfaCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	codes, err := mh.svc.ConfirmTOTP(ctx, payload.UserID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newRecoveryCodesResponse(codes)
+
+	handleSuccess(ctx, rsp)
+}
+
+// DisableTOTP godoc
+//
+//	@Summary		Disable the TOTP authenticator
+//	@Description	Removes the TOTP authenticator and the recovery codes of the current user with a TOTP code or an unused recovery code
+//	@Tags			MFA
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest	true	"TOTP or recovery code request body"
+//	@Success		200		{object}	response		"MFA disabled"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me/mfa/totp/disable [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) DisableTOTP(ctx *gin.Context) {
+	var req mfaCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := mh.svc.DisableTOTP(ctx, payload.UserID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary		Regenerate the recovery codes
+//	@Description	Replaces the recovery codes of the current user with new ones using a TOTP code
+//	@Tags			MFA
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		mfaCodeRequest			true	"TOTP code request body"
+//	@Success		200		{object}	recoveryCodesResponse	"Recovery codes regenerated"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/me/mfa/recovery-codes [post]
+//	@Security		BearerAuth
+func (mh *MFAHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req mfaCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	codes, err := mh.svc.RegenerateRecoveryCodes(ctx, payload.UserID, req.Code)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newRecoveryCodesResponse(codes)
+
+	handleSuccess(ctx, rsp)
+}
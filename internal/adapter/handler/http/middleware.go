@@ -18,7 +18,7 @@ const (
 )
 
 // authMiddleware is a middleware to check if the user is authenticated
-func authMiddleware(token port.TokenService) gin.HandlerFunc {
+func authMiddleware(authService port.AuthService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
@@ -45,7 +45,7 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 		}
 
 		accessToken := fields[1]
-		payload, err := token.VerifyToken([]byte(accessToken))
+		payload, err := authService.VerifyToken(ctx, []byte(accessToken))
 		if err != nil {
 			handleAbort(ctx, err)
 			return
@@ -56,18 +56,33 @@ func authMiddleware(token port.TokenService) gin.HandlerFunc {
 	}
 }
 
-// adminMiddleware is a middleware to check if the user is an admin
-func adminMiddleware() gin.HandlerFunc {
+// permissionMiddleware is a middleware to check if the role of the user grants the permission
+// to perform the action on the resource. If requireMFA is set, the access token has to be issued
+// after the second factor was passed to exercise a permission that requires it, whatever the role is
+func permissionMiddleware(authorizer port.Authorizer, action domain.Action, resource domain.Resource, requireMFA bool) gin.HandlerFunc {
+	permission := domain.Permission{Resource: resource, Action: action}
+
 	return func(ctx *gin.Context) {
 		payload := getAuthPayload(ctx, authorizationPayloadKey)
 
-		isAdmin := payload.Role == domain.Admin
-		if !isAdmin {
+		allowed, err := authorizer.Can(ctx, payload, action, resource)
+		if err != nil {
+			handleAbort(ctx, err)
+			return
+		}
+
+		if !allowed {
 			err := domain.ErrForbidden
 			handleAbort(ctx, err)
 			return
 		}
 
+		if requireMFA && permission.RequiresMFA() && !payload.MFA() {
+			err := domain.ErrMFARequired
+			handleAbort(ctx, err)
+			return
+		}
+
 		ctx.Next()
 	}
 }
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-hexagon/internal/core/port"
+)
+
+// PasswordHandler represents the HTTP handler for password reset-related requests
+type PasswordHandler struct {
+	svc port.PasswordService
+}
+
+// NewPasswordHandler creates a new PasswordHandler instance
+func NewPasswordHandler(svc port.PasswordService) *PasswordHandler {
+	return &PasswordHandler{
+		svc,
+	}
+}
+
+// forgotPasswordRequest represents the request body for requesting a password reset
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"test@example.com"`
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Request a password reset
+//	@Description	Sends a single-use, time-limited password reset token to the user. The response is the same whether the email is registered or not.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		forgotPasswordRequest	true	"Forgot password request body"
+//	@Success		200		{object}	response				"Password reset requested"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/password/forgot [post]
+func (ph *PasswordHandler) ForgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ph.svc.ForgotPassword(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// resetPasswordRequest represents the request body for resetting a password
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" example:"q8F3dPZ0n1wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+	Password string `json:"password" binding:"required" example:"Passw0rd"`
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset a password
+//	@Description	Sets a new password with the password reset token. The token can be used once, and all sessions of the user are revoked.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		resetPasswordRequest	true	"Reset password request body"
+//	@Success		200		{object}	response				"Password reset"
+//	@Failure		400		{object}	errorResponse			"Validation or password policy error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/users/password/reset [post]
+func (ph *PasswordHandler) ResetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := ph.svc.ResetPassword(ctx, []byte(req.Token), req.Password)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
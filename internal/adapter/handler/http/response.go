@@ -1,6 +1,8 @@
 package http
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"golang-hexagon/internal/core/domain"
 	"net/http"
@@ -44,33 +46,177 @@ func newMeta(total, limit, skip uint64) meta {
 
 // authResponse represents an authentication response body
 type authResponse struct {
-	AccessToken string `json:"token" example:"v2.local.Gdh5kiOTyyaQ3_bNykYDeYHO21Jg2..."`
+	AccessToken      string    `json:"token" example:"v4.local.Gdh5kiOTyyaQ3_bNykYDeYHO21Jg2..."`
+	ExpiresAt        time.Time `json:"expires_at" example:"1970-01-01T00:15:00Z"`
+	ExpiresIn        int64     `json:"expires_in" example:"900"`
+	RefreshToken     string    `json:"refresh_token" example:"q8F3dPZ0n1wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at" example:"1970-01-31T00:00:00Z"`
 }
 
 // newAuthResponse is a helper function to create a response body for handling authentication data
-func newAuthResponse(token string) authResponse {
+func newAuthResponse(tokens *domain.TokenPair) authResponse {
 	return authResponse{
-		AccessToken: token,
+		AccessToken:      string(tokens.AccessToken),
+		ExpiresAt:        tokens.ExpiresAt,
+		ExpiresIn:        int64(time.Until(tokens.ExpiresAt).Seconds()),
+		RefreshToken:     string(tokens.RefreshToken),
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
 	}
 }
 
+// mfaChallengeResponse represents the response body of a login that has to be completed with the second factor
+type mfaChallengeResponse struct {
+	MFARequired bool      `json:"mfa_required" example:"true"`
+	MFAToken    string    `json:"mfa_token" example:"Yk1p3Cq0n9wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+	ExpiresAt   time.Time `json:"expires_at" example:"1970-01-01T00:05:00Z"`
+}
+
+// newMFAChallengeResponse is a helper function to create a response body for handling MFA challenges
+func newMFAChallengeResponse(challenge *domain.MFAChallenge) mfaChallengeResponse {
+	return mfaChallengeResponse{
+		MFARequired: true,
+		MFAToken:    string(challenge.Token),
+		ExpiresAt:   challenge.ExpiresAt,
+	}
+}
+
+// totpEnrollmentResponse represents a new TOTP authenticator response body
+type totpEnrollmentResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	URI    string `json:"uri" example:"otpauth://totp/golang-hexagon:test@example.com?issuer=golang-hexagon&secret=JBSWY3DPEHPK3PXP"`
+	QRCode string `json:"qr_code" example:"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAA..."`
+}
+
+// newTOTPEnrollmentResponse is a helper function to create a response body for handling TOTP enrollments
+func newTOTPEnrollmentResponse(enrollment *domain.TOTPEnrollment) totpEnrollmentResponse {
+	return totpEnrollmentResponse{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}
+}
+
+// recoveryCodesResponse represents an MFA recovery codes response body
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"abcdefgh-ijklmnop,qrstuvwx-yz234567"`
+}
+
+// newRecoveryCodesResponse is a helper function to create a response body for handling MFA recovery codes
+func newRecoveryCodesResponse(codes []string) recoveryCodesResponse {
+	return recoveryCodesResponse{
+		RecoveryCodes: codes,
+	}
+}
+
+// publicKeyResponse represents a token public key response body
+type publicKeyResponse struct {
+	ID        string     `json:"kid" example:"2024-10"`
+	Algorithm string     `json:"alg" example:"v4.public"`
+	Key       string     `json:"key" example:"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"1970-01-01T00:00:00Z"`
+}
+
+// publicKeysResponse represents a token public key set response body
+type publicKeysResponse struct {
+	Keys []publicKeyResponse `json:"keys"`
+}
+
+// newPublicKeysResponse is a helper function to create a response body for handling token public keys
+func newPublicKeysResponse(keys []domain.PublicKey) publicKeysResponse {
+	rsp := publicKeysResponse{
+		Keys: make([]publicKeyResponse, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		rsp.Keys = append(rsp.Keys, publicKeyResponse{
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			Key:       hex.EncodeToString(key.Key),
+			ExpiresAt: key.ExpiresAt,
+		})
+	}
+
+	return rsp
+}
+
 // userResponse represents a user response body
 type userResponse struct {
-	ID        uint64    `json:"id" example:"1"`
-	Name      string    `json:"name" example:"John Doe"`
-	Email     string    `json:"email" example:"test@example.com"`
-	CreatedAt time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+	ID         uint64     `json:"id" example:"1"`
+	Name       string     `json:"name" example:"John Doe"`
+	Email      string     `json:"email" example:"test@example.com"`
+	VerifiedAt *time.Time `json:"verified_at" example:"1970-01-01T00:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	UpdatedAt  time.Time  `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" example:"1970-01-01T00:00:00Z"`
 }
 
 // newUserResponse is a helper function to create a response body for handling user data
 func newUserResponse(user *domain.User) userResponse {
 	return userResponse{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		VerifiedAt: user.VerifiedAt,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		DeletedAt:  user.DeletedAt,
+	}
+}
+
+// publicUserResponse represents the public profile of a user shown to users without access to account details
+type publicUserResponse struct {
+	ID        uint64    `json:"id" example:"1"`
+	Name      string    `json:"name" example:"John Doe"`
+	CreatedAt time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newPublicUserResponse is a helper function to create a response body for handling public user profiles
+func newPublicUserResponse(user *domain.User) publicUserResponse {
+	return publicUserResponse{
 		ID:        user.ID,
 		Name:      user.Name,
-		Email:     user.Email,
 		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// permissionResponse represents a permission response body
+type permissionResponse struct {
+	Permission  string `json:"permission" example:"users:read"`
+	Description string `json:"description" example:"List and view users"`
+}
+
+// newPermissionResponse is a helper function to create a response body for handling permission data
+func newPermissionResponse(permission domain.Permission) permissionResponse {
+	return permissionResponse{
+		Permission:  permission.String(),
+		Description: permission.Description,
+	}
+}
+
+// roleResponse represents a role response body
+type roleResponse struct {
+	ID          uint64    `json:"id" example:"1"`
+	Name        string    `json:"name" example:"support"`
+	Description string    `json:"description" example:"Customer support agents"`
+	Permissions []string  `json:"permissions" example:"users:read,users:unlock"`
+	CreatedAt   time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newRoleResponse is a helper function to create a response body for handling role data
+func newRoleResponse(role *domain.Role) roleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.String())
+	}
+
+	return roleResponse{
+		ID:          role.ID,
+		Name:        string(role.Name),
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
 	}
 }
 
@@ -80,12 +226,37 @@ var errorStatusMap = map[error]int{
 	domain.ErrDataNotFound:               http.StatusNotFound,
 	domain.ErrConflictingData:            http.StatusConflict,
 	domain.ErrInvalidCredentials:         http.StatusUnauthorized,
+	domain.ErrExpiredToken:               http.StatusUnauthorized,
+	domain.ErrInvalidToken:               http.StatusUnauthorized,
+	domain.ErrRevokedToken:               http.StatusUnauthorized,
+	domain.ErrInvalidRefreshToken:        http.StatusUnauthorized,
+	domain.ErrRefreshTokenReused:         http.StatusUnauthorized,
 	domain.ErrUnauthorized:               http.StatusUnauthorized,
 	domain.ErrEmptyAuthorizationHeader:   http.StatusUnauthorized,
 	domain.ErrInvalidAuthorizationHeader: http.StatusUnauthorized,
 	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
 	domain.ErrForbidden:                  http.StatusForbidden,
+	domain.ErrAccountLocked:              http.StatusLocked,
+	domain.ErrTooManyLoginAttempts:       http.StatusTooManyRequests,
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
+	domain.ErrInvalidResetToken:          http.StatusBadRequest,
+	domain.ErrInvalidVerificationToken:   http.StatusBadRequest,
+	domain.ErrVerificationRateLimited:    http.StatusTooManyRequests,
+	domain.ErrEmailNotVerified:           http.StatusForbidden,
+	domain.ErrWeakPassword:               http.StatusBadRequest,
+	domain.ErrInvalidMFAToken:            http.StatusUnauthorized,
+	domain.ErrInvalidMFACode:             http.StatusUnauthorized,
+	domain.ErrMFAAlreadyEnabled:          http.StatusConflict,
+	domain.ErrMFANotEnabled:              http.StatusBadRequest,
+	domain.ErrMFARequired:                http.StatusForbidden,
+	domain.ErrInvalidCurrentPassword:     http.StatusForbidden,
+	domain.ErrVersionMismatch:            http.StatusPreconditionFailed,
+	domain.ErrVersionRequired:            http.StatusPreconditionRequired,
+	domain.ErrSelfRoleChange:             http.StatusForbidden,
+	domain.ErrUnknownRole:                http.StatusBadRequest,
+	domain.ErrUnknownPermission:          http.StatusBadRequest,
+	domain.ErrRoleInUse:                  http.StatusConflict,
+	domain.ErrBuiltInRole:                http.StatusConflict,
 }
 
 // validationError sends an error response for some specific request validation error
@@ -97,36 +268,48 @@ func validationError(ctx *gin.Context, err error) {
 
 // handleError determines the status code of an error and returns a JSON response with the error message and status code
 func handleError(ctx *gin.Context, err error) {
-	statusCode, ok := errorStatusMap[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
-	}
-
+	statusCode := errorStatus(err)
 	errMsg := parseError(err)
 	errRsp := newErrorResponse(errMsg)
+	errRsp.Violations = parseViolations(err)
 	ctx.JSON(statusCode, errRsp)
 }
 
 // handleAbort sends an error response and aborts the request with the specified status code and error message
 func handleAbort(ctx *gin.Context, err error) {
+	statusCode := errorStatus(err)
+	errMsg := parseError(err)
+	errRsp := newErrorResponse(errMsg)
+	ctx.AbortWithStatusJSON(statusCode, errRsp)
+}
+
+// errorStatus determines the http status code of the error
+func errorStatus(err error) int {
+	if errors.Is(err, domain.ErrWeakPassword) {
+		err = domain.ErrWeakPassword
+	}
+
 	statusCode, ok := errorStatusMap[err]
 	if !ok {
-		statusCode = http.StatusInternalServerError
+		return http.StatusInternalServerError
 	}
 
-	errMsg := parseError(err)
-	errRsp := newErrorResponse(errMsg)
-	ctx.AbortWithStatusJSON(statusCode, errRsp)
+	return statusCode
 }
 
 // parseError parses error messages from the error object and returns a slice of error messages
 func parseError(err error) []string {
 	var errMsgs []string
+	var policyErr *domain.PasswordPolicyError
 
 	if errors.As(err, &validator.ValidationErrors{}) {
 		for _, err := range err.(validator.ValidationErrors) {
 			errMsgs = append(errMsgs, err.Error())
 		}
+	} else if errors.As(err, &policyErr) {
+		for _, violation := range policyErr.Violations {
+			errMsgs = append(errMsgs, violation.Message)
+		}
 	} else {
 		errMsgs = append(errMsgs, err.Error())
 	}
@@ -134,10 +317,35 @@ func parseError(err error) []string {
 	return errMsgs
 }
 
+// parseViolations returns the broken password policy rules of the error, if any
+func parseViolations(err error) []violationResponse {
+	var policyErr *domain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+
+	violations := make([]violationResponse, 0, len(policyErr.Violations))
+	for _, violation := range policyErr.Violations {
+		violations = append(violations, violationResponse{
+			Rule:    string(violation.Rule),
+			Message: violation.Message,
+		})
+	}
+
+	return violations
+}
+
+// violationResponse represents a broken password policy rule
+type violationResponse struct {
+	Rule    string `json:"rule" example:"min_length"`
+	Message string `json:"message" example:"password must be at least 8 characters long"`
+}
+
 // errorResponse represents an error response body format
 type errorResponse struct {
-	Success  bool     `json:"success" example:"false"`
-	Messages []string `json:"messages" example:"Error message 1, Error message 2"`
+	Success    bool                `json:"success" example:"false"`
+	Messages   []string            `json:"messages" example:"Error message 1, Error message 2"`
+	Violations []violationResponse `json:"violations,omitempty"`
 }
 
 // newErrorResponse is a helper function to create an error response body
@@ -153,3 +361,9 @@ func handleSuccess(ctx *gin.Context, data any) {
 	rsp := newResponse(true, "Success", data)
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+// handleAccepted sends an accepted response for a request that has to be completed with another request
+func handleAccepted(ctx *gin.Context, data any) {
+	rsp := newResponse(true, "Accepted", data)
+	ctx.JSON(http.StatusAccepted, rsp)
+}
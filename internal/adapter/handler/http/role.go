@@ -0,0 +1,313 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+)
+
+// RoleHandler represents the HTTP handler for role-related requests
+type RoleHandler struct {
+	svc        port.RoleService
+	authorizer port.Authorizer
+}
+
+// NewRoleHandler creates a new RoleHandler instance
+func NewRoleHandler(svc port.RoleService, authorizer port.Authorizer) *RoleHandler {
+	return &RoleHandler{
+		svc,
+		authorizer,
+	}
+}
+
+// createRoleRequest represents the request body for creating a role
+type createRoleRequest struct {
+	Name        domain.UserRole `json:"name" binding:"required,user_role" example:"support"`
+	Description string          `json:"description" example:"Customer support agents"`
+	Permissions []string        `json:"permissions" example:"users:read,users:unlock"`
+}
+
+// CreateRole godoc
+//
+//	@Summary		Create a role
+//	@Description	Create a new role with the given permissions in the "resource:action" format.
+//	@Description	Users can only grant permissions they hold themselves. The permissions of the built-in admin and basic roles can not be changed
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			createRoleRequest	body		createRoleRequest	true	"Create role request"
+//	@Success		200					{object}	roleResponse		"Role created"
+//	@Failure		400					{object}	errorResponse		"Validation or unknown permission error"
+//	@Failure		401					{object}	errorResponse		"Unauthorized error"
+//	@Failure		403					{object}	errorResponse		"Forbidden error"
+//	@Failure		409					{object}	errorResponse		"Data conflict error"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/v1/roles [post]
+//	@Security		BearerAuth
+func (rh *RoleHandler) CreateRole(ctx *gin.Context) {
+	var req createRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	permissions, err := toPermissions(req.Permissions)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	err = rh.canGrant(ctx, permissions)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	role, err := rh.svc.CreateRole(ctx, &domain.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newRoleResponse(role)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listRolesRequest represents the request body for listing roles
+type listRolesRequest struct {
+	Skip  uint64 `form:"skip" binding:"required,min=0" example:"0"`
+	Limit uint64 `form:"limit" binding:"required,min=5" example:"5"`
+}
+
+// ListRoles godoc
+//
+//	@Summary		List roles
+//	@Description	List roles with their permissions with pagination
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		uint64			true	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Roles displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/roles [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) ListRoles(ctx *gin.Context) {
+	var req listRolesRequest
+	var rolesList []roleResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	roles, err := rh.svc.ListRoles(ctx, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, role := range roles {
+		rolesList = append(rolesList, newRoleResponse(role))
+	}
+
+	total := uint64(len(rolesList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, rolesList, "roles")
+
+	handleSuccess(ctx, rsp)
+}
+
+// getRoleRequest represents the request body for getting a role
+type getRoleRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// GetRole godoc
+//
+//	@Summary		Get a role
+//	@Description	Get a role with its permissions by id
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Role ID"
+//	@Success		200	{object}	roleResponse	"Role displayed"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/roles/{id} [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) GetRole(ctx *gin.Context) {
+	var req getRoleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	role, err := rh.svc.GetRole(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newRoleResponse(role)
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateRoleRequest represents the request body for updating a role
+type updateRoleRequest struct {
+	Description string   `json:"description" example:"Customer support agents"`
+	Permissions []string `json:"permissions" example:"users:read,users:unlock"`
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Update a role
+//	@Description	Update the description of a role or replace its permissions by id. The name of a role can not be changed.
+//	@Description	Users can only grant permissions they hold themselves
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		uint64				true	"Role ID"
+//	@Param			updateRoleRequest	body		updateRoleRequest	true	"Update role request"
+//	@Success		200					{object}	roleResponse		"Role updated"
+//	@Failure		400					{object}	errorResponse		"Validation or unknown permission error"
+//	@Failure		401					{object}	errorResponse		"Unauthorized error"
+//	@Failure		403					{object}	errorResponse		"Forbidden error"
+//	@Failure		404					{object}	errorResponse		"Data not found error"
+//	@Failure		409					{object}	errorResponse		"Built-in role error"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/v1/roles/{id} [put]
+//	@Security		BearerAuth
+func (rh *RoleHandler) UpdateRole(ctx *gin.Context) {
+	var req updateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	idStr := ctx.Param("id")
+	id, err := stringToUint64(idStr)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	permissions, err := toPermissions(req.Permissions)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	err = rh.canGrant(ctx, permissions)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	role, err := rh.svc.UpdateRole(ctx, &domain.Role{
+		ID:          id,
+		Description: req.Description,
+		Permissions: permissions,
+	})
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newRoleResponse(role)
+
+	handleSuccess(ctx, rsp)
+}
+
+// deleteRoleRequest represents the request body for deleting a role
+type deleteRoleRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// DeleteRole godoc
+//
+//	@Summary		Delete a role
+//	@Description	Delete a role that is not assigned to any user by id. Built-in roles can not be deleted
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"Role ID"
+//	@Success		200	{object}	response		"Role deleted"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		409	{object}	errorResponse	"Role in use or built-in role error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/roles/{id} [delete]
+//	@Security		BearerAuth
+func (rh *RoleHandler) DeleteRole(ctx *gin.Context) {
+	var req deleteRoleRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := rh.svc.DeleteRole(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// ListPermissions godoc
+//
+//	@Summary		List permissions
+//	@Description	List all permissions that can be granted to roles
+//	@Tags			Roles
+//	@Produce		json
+//	@Success		200	{array}		permissionResponse	"Permissions displayed"
+//	@Failure		401	{object}	errorResponse		"Unauthorized error"
+//	@Failure		403	{object}	errorResponse		"Forbidden error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/v1/roles/permissions [get]
+//	@Security		BearerAuth
+func (rh *RoleHandler) ListPermissions(ctx *gin.Context) {
+	permissions, err := rh.svc.ListPermissions(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := make([]permissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		rsp = append(rsp, newPermissionResponse(permission))
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// canGrant checks that the authenticated user holds every one of the permissions it grants to a role
+func (rh *RoleHandler) canGrant(ctx *gin.Context, permissions []domain.Permission) error {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	allowed, err := rh.authorizer.CanGrant(ctx, payload, permissions)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
@@ -2,8 +2,10 @@ package http
 
 import (
 	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port"
 	"log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/gin-contrib/cors"
@@ -23,9 +25,15 @@ type Router struct {
 // NewRouter creates a new Config router
 func NewRouter(
 	conf *config.Container,
-	token port.TokenService,
+	authService port.AuthService,
+	authorizer port.Authorizer,
 	userHandler UserHandler,
-	authHandler AuthHandler) (*Router, error) {
+	authHandler AuthHandler,
+	passwordHandler PasswordHandler,
+	verificationHandler VerificationHandler,
+	mfaHandler MFAHandler,
+	roleHandler RoleHandler,
+	keyHandler KeyHandler) (*Router, error) {
 	// Disable debug mode in production
 	if conf.App.Env == config.EnvProduction {
 		gin.SetMode(gin.ReleaseMode)
@@ -38,8 +46,26 @@ func NewRouter(
 	allowedOrigins := conf.HTTP.AllowedOrigins
 	originsList := strings.Split(allowedOrigins, ",")
 	ginConfig.AllowOrigins = originsList
+	// browsers only let clients read and send the versions of users if they are allowed explicitly
+	ginConfig.AddAllowHeaders("If-Match")
+	ginConfig.AddExposeHeaders("ETag")
+
+	requireMFA, err := strconv.ParseBool(conf.Auth.MFARequiredForAdmins)
+	if err != nil {
+		return nil, err
+	}
 
 	router := gin.New()
+
+	// Client IPs, which limit the failed logins, are only taken from the forwarding headers of trusted proxies
+	var trustedProxies []string
+	if conf.HTTP.TrustedProxies != "" {
+		trustedProxies = strings.Split(conf.HTTP.TrustedProxies, ",")
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
 	router.Use(sloggin.New(slog.Default()), gin.Recovery(), cors.New(ginConfig))
 
 	// Custom validators
@@ -50,28 +76,62 @@ func NewRouter(
 		}
 	}
 
+	// Permission guards
+	can := func(action domain.Action, resource domain.Resource) gin.HandlerFunc {
+		return permissionMiddleware(authorizer, action, resource, requireMFA)
+	}
+
 	// Swagger
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Token public keys
+	router.GET("/.well-known/token-keys", keyHandler.PublicKeys)
+
 	v1 := router.Group("/v1")
 	{
 		user := v1.Group("/users")
 		{
 			user.POST("", userHandler.Register)
 			user.POST("/login", authHandler.Login)
+			user.POST("/login/mfa", authHandler.VerifyMFA)
+			user.POST("/refresh", authHandler.Refresh)
+			user.POST("/password/forgot", passwordHandler.ForgotPassword)
+			user.POST("/password/reset", passwordHandler.ResetPassword)
+			user.GET("/verify", verificationHandler.VerifyEmailLink)
+			user.POST("/verify", verificationHandler.VerifyEmail)
+			user.POST("/verify/resend", verificationHandler.ResendVerification)
 
-			authUser := user.Group("/").Use(authMiddleware(token))
+			authUser := user.Group("/").Use(authMiddleware(authService))
 			{
-				authUser.GET("/", userHandler.ListUsers)
-				authUser.GET("/:id", userHandler.GetUser)
-
-				admin := authUser.Use(adminMiddleware())
-				{
-					admin.PUT("/:id", userHandler.UpdateUser)
-					admin.DELETE("/:id", userHandler.DeleteUser)
-				}
+				authUser.GET("/", can(domain.ActionRead, domain.ResourceUsers), userHandler.ListUsers)
+				authUser.GET("/:id", can(domain.ActionRead, domain.ResourceUsers), userHandler.GetUser)
+				authUser.POST("/logout", authHandler.Logout)
+				authUser.GET("/me", userHandler.GetMe)
+				authUser.PATCH("/me", userHandler.UpdateMe)
+				authUser.DELETE("/me", userHandler.DeleteMe)
+				authUser.POST("/me/mfa/totp", mfaHandler.EnrollTOTP)
+				authUser.POST("/me/mfa/totp/confirm", mfaHandler.ConfirmTOTP)
+				authUser.POST("/me/mfa/totp/disable", mfaHandler.DisableTOTP)
+				authUser.POST("/me/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+
+				authUser.PUT("/:id", can(domain.ActionUpdate, domain.ResourceUsers), userHandler.UpdateUser)
+				authUser.DELETE("/:id", can(domain.ActionDelete, domain.ResourceUsers), userHandler.DeleteUser)
+				authUser.POST("/:id/revoke", can(domain.ActionRevoke, domain.ResourceSessions), authHandler.RevokeUserTokens)
+				authUser.POST("/:id/unlock", can(domain.ActionUnlock, domain.ResourceUsers), authHandler.UnlockUser)
+				authUser.GET("/deleted", can(domain.ActionRestore, domain.ResourceUsers), userHandler.ListDeletedUsers)
+				authUser.POST("/:id/restore", can(domain.ActionRestore, domain.ResourceUsers), userHandler.RestoreUser)
+				authUser.DELETE("/:id/purge", can(domain.ActionPurge, domain.ResourceUsers), userHandler.PurgeUser)
 			}
 		}
+		role := v1.Group("/roles").Use(authMiddleware(authService))
+		{
+			role.GET("", can(domain.ActionRead, domain.ResourceRoles), roleHandler.ListRoles)
+			role.GET("/permissions", can(domain.ActionRead, domain.ResourceRoles), roleHandler.ListPermissions)
+			role.GET("/:id", can(domain.ActionRead, domain.ResourceRoles), roleHandler.GetRole)
+			role.POST("", can(domain.ActionCreate, domain.ResourceRoles), roleHandler.CreateRole)
+			role.PUT("/:id", can(domain.ActionUpdate, domain.ResourceRoles), roleHandler.UpdateRole)
+			role.DELETE("/:id", can(domain.ActionDelete, domain.ResourceRoles), roleHandler.DeleteRole)
+		}
 	}
 
 	return &Router{
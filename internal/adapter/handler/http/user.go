@@ -6,15 +6,20 @@ import (
 	"golang-hexagon/internal/core/port"
 )
 
-// UserHandler represents the HTTP handler for user-related requests
+// UserHandler represents the HTTP handler for user-related requests.
+// Updates and deletes of users are rejected without an If-Match header if requireIfMatch is set
 type UserHandler struct {
-	svc port.UserService
+	svc            port.UserService
+	authorizer     port.Authorizer
+	requireIfMatch bool
 }
 
 // NewUserHandler creates a new UserHandler instance
-func NewUserHandler(svc port.UserService) *UserHandler {
+func NewUserHandler(svc port.UserService, authorizer port.Authorizer, requireIfMatch bool) *UserHandler {
 	return &UserHandler{
 		svc,
+		authorizer,
+		requireIfMatch,
 	}
 }
 
@@ -22,7 +27,7 @@ func NewUserHandler(svc port.UserService) *UserHandler {
 type registerRequest struct {
 	Name     string `json:"name" binding:"required" example:"John Doe"`
 	Email    string `json:"email" binding:"required,email" example:"test@example.com"`
-	Password string `json:"password" binding:"required,min=8" example:"12345678"`
+	Password string `json:"password" binding:"required" example:"Passw0rd"`
 }
 
 // Register godoc
@@ -34,7 +39,7 @@ type registerRequest struct {
 //	@Produce		json
 //	@Param			registerRequest	body		registerRequest	true	"Register request"
 //	@Success		200				{object}	userResponse	"User created"
-//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		400				{object}	errorResponse	"Validation or password policy error"
 //	@Failure		401				{object}	errorResponse	"Unauthorized error"
 //	@Failure		404				{object}	errorResponse	"Data not found error"
 //	@Failure		409				{object}	errorResponse	"Data conflict error"
@@ -73,7 +78,7 @@ type listUsersRequest struct {
 // ListUsers godoc
 //
 //	@Summary		List users
-//	@Description	List users with pagination
+//	@Description	List users with pagination. Users without the permission to read account details get the public profiles of other users
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
@@ -81,18 +86,28 @@ type listUsersRequest struct {
 //	@Param			limit	query		uint64			true	"Limit"
 //	@Success		200		{object}	meta			"Users displayed"
 //	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
 //	@Failure		500		{object}	errorResponse	"Internal server error"
 //	@Router			/v1/users [get]
 //	@Security		BearerAuth
 func (uh *UserHandler) ListUsers(ctx *gin.Context) {
 	var req listUsersRequest
-	var usersList []userResponse
+	var usersList []any
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		validationError(ctx, err)
 		return
 	}
 
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	readDetails, err := uh.authorizer.Can(ctx, payload, domain.ActionReadDetails, domain.ResourceUsers)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
 	users, err := uh.svc.ListUsers(ctx, req.Skip, req.Limit)
 	if err != nil {
 		handleError(ctx, err)
@@ -100,7 +115,7 @@ func (uh *UserHandler) ListUsers(ctx *gin.Context) {
 	}
 
 	for _, user := range users {
-		usersList = append(usersList, newUserResponse(user))
+		usersList = append(usersList, newUserProfileResponse(user, payload, readDetails))
 	}
 
 	total := uint64(len(usersList))
@@ -118,15 +133,20 @@ type getUserRequest struct {
 // GetUser godoc
 //
 //	@Summary		Get a user
-//	@Description	Get a user by id
+//	@Description	Get a user by id. Users without the permission to read account details get the public profile of other users.
+//	@Description	The ETag header holds the version of the user, to be sent in the If-Match header of updates and deletes
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		uint64			true	"User ID"
-//	@Success		200	{object}	userResponse	"User displayed"
-//	@Failure		400	{object}	errorResponse	"Validation error"
-//	@Failure		404	{object}	errorResponse	"Data not found error"
-//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Param			id	path		uint64				true	"User ID"
+//	@Success		200	{object}	userResponse		"User displayed"
+//	@Header			200	{string}	ETag				"Version of the user"
+//	@Success		200	{object}	publicUserResponse	"Public user profile displayed"
+//	@Failure		400	{object}	errorResponse		"Validation error"
+//	@Failure		401	{object}	errorResponse		"Unauthorized error"
+//	@Failure		403	{object}	errorResponse		"Forbidden error"
+//	@Failure		404	{object}	errorResponse		"Data not found error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
 //	@Router			/v1/users/{id} [get]
 //	@Security		BearerAuth
 func (uh *UserHandler) GetUser(ctx *gin.Context) {
@@ -136,14 +156,23 @@ func (uh *UserHandler) GetUser(ctx *gin.Context) {
 		return
 	}
 
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	readDetails, err := uh.authorizer.Can(ctx, payload, domain.ActionReadDetails, domain.ResourceUsers)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
 	user, err := uh.svc.GetUser(ctx, req.ID)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	rsp := newUserResponse(user)
+	rsp := newUserProfileResponse(user, payload, readDetails)
 
+	setETag(ctx, user)
 	handleSuccess(ctx, rsp)
 }
 
@@ -151,24 +180,30 @@ func (uh *UserHandler) GetUser(ctx *gin.Context) {
 type updateUserRequest struct {
 	Name     string          `json:"name" binding:"omitempty,required" example:"John Doe"`
 	Email    string          `json:"email" binding:"omitempty,required,email" example:"test@example.com"`
-	Password string          `json:"password" binding:"omitempty,required,min=8" example:"12345678"`
+	Password string          `json:"password" binding:"omitempty,required" example:"Passw0rd"`
 	Role     domain.UserRole `json:"role" binding:"omitempty,required,user_role" example:"admin"`
 }
 
 // UpdateUser godoc
 //
 //	@Summary		Update a user
-//	@Description	Update a user's name, email, password, or role by id
+//	@Description	Update a user's name, email, password, or role by id. The role has to exist, and users can not change their own role.
+//	@Description	Users can only assign roles whose permissions they all hold themselves.
+//	@Description	With an If-Match header the user is only updated if the ETag is still its current version
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id					path		uint64				true	"User ID"
+//	@Param			If-Match			header		string				false	"ETag of the user, required if enforced"
 //	@Param			updateUserRequest	body		updateUserRequest	true	"Update user request"
 //	@Success		200					{object}	userResponse		"User updated"
-//	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Header			200					{string}	ETag				"Version of the updated user"
+//	@Failure		400					{object}	errorResponse		"Validation, password policy or unknown role error"
 //	@Failure		401					{object}	errorResponse		"Unauthorized error"
 //	@Failure		403					{object}	errorResponse		"Forbidden error"
 //	@Failure		404					{object}	errorResponse		"Data not found error"
+//	@Failure		412					{object}	errorResponse		"Version mismatch error"
+//	@Failure		428					{object}	errorResponse		"Version required error"
 //	@Failure		500					{object}	errorResponse		"Internal server error"
 //	@Router			/v1/users/{id} [put]
 //	@Security		BearerAuth
@@ -186,12 +221,38 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+	if id == payload.UserID && req.Role != "" {
+		handleError(ctx, domain.ErrSelfRoleChange)
+		return
+	}
+
+	if req.Role != "" {
+		allowed, err := uh.authorizer.CanAssign(ctx, payload, req.Role)
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+
+		if !allowed {
+			handleError(ctx, domain.ErrForbidden)
+			return
+		}
+	}
+
+	version, err := ifMatchVersion(ctx, uh.requireIfMatch)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
 	user := domain.User{
 		ID:       id,
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
 		Role:     req.Role,
+		Version:  version,
 	}
 
 	_, err = uh.svc.UpdateUser(ctx, &user)
@@ -202,6 +263,7 @@ func (uh *UserHandler) UpdateUser(ctx *gin.Context) {
 
 	rsp := newUserResponse(&user)
 
+	setETag(ctx, &user)
 	handleSuccess(ctx, rsp)
 }
 
@@ -213,27 +275,260 @@ type deleteUserRequest struct {
 // DeleteUser godoc
 //
 //	@Summary		Delete a user
-//	@Description	Delete a user by id
+//	@Description	Delete a user by id. The user can be restored until it is purged after the retention.
+//	@Description	With an If-Match header the user is only deleted if the ETag is still its current version
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		uint64			true	"User ID"
+//	@Param			If-Match	header		string			false	"ETag of the user, required if enforced"
+//	@Success		200			{object}	response		"User deleted"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		401			{object}	errorResponse	"Unauthorized error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		404			{object}	errorResponse	"Data not found error"
+//	@Failure		412			{object}	errorResponse	"Version mismatch error"
+//	@Failure		428			{object}	errorResponse	"Version required error"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/{id} [delete]
+//	@Security		BearerAuth
+func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
+	var req deleteUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	version, err := ifMatchVersion(ctx, uh.requireIfMatch)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	err = uh.svc.DeleteUser(ctx, req.ID, version)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// ListDeletedUsers godoc
+//
+//	@Summary		List deleted users
+//	@Description	List the deleted users that can still be restored with pagination, the most recently deleted first
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		uint64			true	"Skip"
+//	@Param			limit	query		uint64			true	"Limit"
+//	@Success		200		{object}	meta			"Deleted users displayed"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		401		{object}	errorResponse	"Unauthorized error"
+//	@Failure		403		{object}	errorResponse	"Forbidden error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/deleted [get]
+//	@Security		BearerAuth
+func (uh *UserHandler) ListDeletedUsers(ctx *gin.Context) {
+	var req listUsersRequest
+	var usersList []userResponse
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	users, err := uh.svc.ListDeletedUsers(ctx, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	for _, user := range users {
+		usersList = append(usersList, newUserResponse(user))
+	}
+
+	total := uint64(len(usersList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, usersList, "users")
+
+	handleSuccess(ctx, rsp)
+}
+
+// restoreUserRequest represents the request body for restoring a deleted user
+type restoreUserRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// RestoreUser godoc
+//
+//	@Summary		Restore a deleted user
+//	@Description	Restore a deleted user by id that is not purged yet. The user has to log in again
 //	@Tags			Users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		uint64			true	"User ID"
-//	@Success		200	{object}	response		"User deleted"
+//	@Success		200	{object}	userResponse	"User restored"
+//	@Header			200	{string}	ETag			"Version of the restored user"
 //	@Failure		400	{object}	errorResponse	"Validation error"
 //	@Failure		401	{object}	errorResponse	"Unauthorized error"
 //	@Failure		403	{object}	errorResponse	"Forbidden error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/v1/users/{id} [delete]
+//	@Router			/v1/users/{id}/restore [post]
 //	@Security		BearerAuth
-func (uh *UserHandler) DeleteUser(ctx *gin.Context) {
-	var req deleteUserRequest
+func (uh *UserHandler) RestoreUser(ctx *gin.Context) {
+	var req restoreUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	user, err := uh.svc.RestoreUser(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newUserResponse(user)
+
+	setETag(ctx, user)
+	handleSuccess(ctx, rsp)
+}
+
+// purgeUserRequest represents the request body for permanently deleting a deleted user
+type purgeUserRequest struct {
+	ID uint64 `uri:"id" binding:"required,min=1" example:"1"`
+}
+
+// PurgeUser godoc
+//
+//	@Summary		Purge a deleted user
+//	@Description	Permanently delete a deleted user by id, after which its email can be registered again
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		uint64			true	"User ID"
+//	@Success		200	{object}	response		"User purged"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/{id}/purge [delete]
+//	@Security		BearerAuth
+func (uh *UserHandler) PurgeUser(ctx *gin.Context) {
+	var req purgeUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
 		validationError(ctx, err)
 		return
 	}
 
-	err := uh.svc.DeleteUser(ctx, req.ID)
+	err := uh.svc.PurgeUser(ctx, req.ID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// GetMe godoc
+//
+//	@Summary		Get the current user
+//	@Description	Get the profile of the authenticated user
+//	@Tags			Users
+//	@Produce		json
+//	@Success		200	{object}	userResponse	"User displayed"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me [get]
+//	@Security		BearerAuth
+func (uh *UserHandler) GetMe(ctx *gin.Context) {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	user, err := uh.svc.GetUser(ctx, payload.UserID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newUserResponse(user)
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateMeRequest represents the request body for updating the current user
+type updateMeRequest struct {
+	Name            string `json:"name" binding:"omitempty,required" example:"John Doe"`
+	Email           string `json:"email" binding:"omitempty,required,email" example:"test@example.com"`
+	Password        string `json:"password" binding:"omitempty,required" example:"N3w-Passw0rd"`
+	CurrentPassword string `json:"current_password" example:"Passw0rd"`
+}
+
+// UpdateMe godoc
+//
+//	@Summary		Update the current user
+//	@Description	Update the name, email, or password of the authenticated user. The current password is required to change the email or the password.
+//	@Description	Changing the password signs the user out of all other sessions, and changing the email requires to verify it again
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			updateMeRequest	body		updateMeRequest	true	"Update current user request"
+//	@Success		200				{object}	userResponse	"User updated"
+//	@Failure		400				{object}	errorResponse	"Validation or password policy error"
+//	@Failure		401				{object}	errorResponse	"Unauthorized error"
+//	@Failure		403				{object}	errorResponse	"Invalid current password error"
+//	@Failure		404				{object}	errorResponse	"Data not found error"
+//	@Failure		409				{object}	errorResponse	"Data conflict error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me [patch]
+//	@Security		BearerAuth
+func (uh *UserHandler) UpdateMe(ctx *gin.Context) {
+	var req updateMeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	user := domain.User{
+		ID:       payload.UserID,
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+	}
+
+	_, err := uh.svc.UpdateProfile(ctx, payload, &user, req.CurrentPassword)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newUserResponse(&user)
+
+	handleSuccess(ctx, rsp)
+}
+
+// DeleteMe godoc
+//
+//	@Summary		Delete the current user
+//	@Description	Delete the account of the authenticated user
+//	@Tags			Users
+//	@Produce		json
+//	@Success		200	{object}	response		"User deleted"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/me [delete]
+//	@Security		BearerAuth
+func (uh *UserHandler) DeleteMe(ctx *gin.Context) {
+	payload := getAuthPayload(ctx, authorizationPayloadKey)
+
+	err := uh.svc.DeleteUser(ctx, payload.UserID, 0)
 	if err != nil {
 		handleError(ctx, err)
 		return
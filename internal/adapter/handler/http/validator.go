@@ -3,15 +3,15 @@ package http
 import (
 	"github.com/go-playground/validator/v10"
 	"golang-hexagon/internal/core/domain"
+	"regexp"
 )
 
+// userRolePattern is the format of role names.
+// Roles are managed at runtime, so the existence of a role is checked when it is assigned
+var userRolePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
 var userRoleValidator validator.Func = func(fl validator.FieldLevel) bool {
 	userRole := fl.Field().Interface().(domain.UserRole)
 
-	switch userRole {
-	case domain.Admin, domain.Basic:
-		return true
-	default:
-		return false
-	}
+	return userRolePattern.MatchString(string(userRole))
 }
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"golang-hexagon/internal/core/port"
+)
+
+// VerificationHandler represents the HTTP handler for email verification-related requests
+type VerificationHandler struct {
+	svc port.VerificationService
+}
+
+// NewVerificationHandler creates a new VerificationHandler instance
+func NewVerificationHandler(svc port.VerificationService) *VerificationHandler {
+	return &VerificationHandler{
+		svc,
+	}
+}
+
+// verifyEmailRequest represents the request body or query for verifying an email address
+type verifyEmailRequest struct {
+	Token string `json:"token" form:"token" binding:"required" example:"q8F3dPZ0n1wQm1yk9R2c8bVx4sLh7TgE5aJ6uYiOpKs"`
+}
+
+// VerifyEmailLink godoc
+//
+//	@Summary		Verify an email address from the link
+//	@Description	Verifies the email address with the token from the link sent on registration
+//	@Tags			Users
+//	@Produce		json
+//	@Param			token	query		string			true	"Verification token"
+//	@Success		200		{object}	response		"Email verified"
+//	@Failure		400		{object}	errorResponse	"Validation error"
+//	@Failure		500		{object}	errorResponse	"Internal server error"
+//	@Router			/v1/users/verify [get]
+func (vh *VerificationHandler) VerifyEmailLink(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	vh.verifyEmail(ctx, req)
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify an email address
+//	@Description	Verifies the email address with the token sent on registration
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		verifyEmailRequest	true	"Verify email request body"
+//	@Success		200		{object}	response			"Email verified"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/users/verify [post]
+func (vh *VerificationHandler) VerifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	vh.verifyEmail(ctx, req)
+}
+
+// verifyEmail verifies the email address with the token of the request
+func (vh *VerificationHandler) verifyEmail(ctx *gin.Context, req verifyEmailRequest) {
+	err := vh.svc.VerifyEmail(ctx, []byte(req.Token))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
+
+// resendVerificationRequest represents the request body for resending the verification email
+type resendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"test@example.com"`
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Resend the verification email
+//	@Description	Sends a new email verification token to the unverified user. The response is the same whether the email is registered or not.
+//	@Tags			Users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		resendVerificationRequest	true	"Resend verification request body"
+//	@Success		200		{object}	response					"Verification email sent"
+//	@Failure		400		{object}	errorResponse				"Validation error"
+//	@Failure		429		{object}	errorResponse				"Too many requests error"
+//	@Failure		500		{object}	errorResponse				"Internal server error"
+//	@Router			/v1/users/verify/resend [post]
+func (vh *VerificationHandler) ResendVerification(ctx *gin.Context) {
+	var req resendVerificationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := vh.svc.ResendVerification(ctx, req.Email)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
@@ -0,0 +1,123 @@
+package rmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const connFormat = "amqp://%s:%s@%s:%s/%s"
+
+// ConnectionState is an enum for the state of the connection to the broker
+type ConnectionState string
+
+// ConnectionState enum values
+const (
+	StateDisconnected ConnectionState = "disconnected"
+	StateConnecting   ConnectionState = "connecting"
+	StateConnected    ConnectionState = "connected"
+)
+
+// State returns the state of the connection to the broker, it is safe to call from health checks
+func (r *MessageHandler) State() ConnectionState {
+	r.stateMu.RLock()
+	defer r.stateMu.RUnlock()
+
+	return r.state
+}
+
+// setState sets the state of the connection to the broker
+func (r *MessageHandler) setState(state ConnectionState) {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
+
+	if r.state != state {
+		slog.Info("RabbitMQ connection state changed", "from", r.state, "to", state)
+	}
+	r.state = state
+}
+
+// connect connects to the broker, retrying with exponential backoff until it succeeds or the context is done.
+// It fails fast if the broker refuses the topology
+func (r *MessageHandler) connect(ctx context.Context) error {
+	r.setState(StateConnecting)
+
+	delay := r.minDelay
+	for attempt := 1; ; attempt++ {
+		err := r.dial()
+		if err == nil {
+			r.setState(StateConnected)
+			slog.Info("Successfully connected to RabbitMQ instance!")
+			return nil
+		}
+
+		if isTopologyError(err) {
+			r.setState(StateDisconnected)
+			return err
+		}
+
+		wait, next := backoff(delay, r.maxDelay)
+		slog.Error("Error connecting to RabbitMQ instance", "error", err, "attempt", attempt, "retry_in", wait)
+
+		select {
+		case <-ctx.Done():
+			r.setState(StateDisconnected)
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay = next
+	}
+}
+
+// backoff returns the wait before the next attempt, the delay with up to half of it as jitter,
+// and the delay of the attempt after it, doubled up to the max delay.
+// The jitter keeps consumers from reconnecting in lockstep after a broker restart
+func backoff(delay, maxDelay time.Duration) (time.Duration, time.Duration) {
+	wait := delay + rand.N(delay/2+1)
+
+	return wait, min(delay*2, maxDelay)
+}
+
+// dial opens the connection and the channel and declares the topology the handler relies on.
+// Declaring is idempotent, so it is repeated after every reconnect in case the broker lost it
+func (r *MessageHandler) dial() error {
+	conn, err := amqp.Dial(fmt.Sprintf(connFormat, r.conf.RMQ.User, r.conf.RMQ.Password, r.conf.RMQ.Host, r.conf.RMQ.Port, r.conf.RMQ.Vhost))
+	if err != nil {
+		return err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	err = r.topology.declare(ch, r.passive)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	r.conn, r.ch = conn, ch
+
+	return nil
+}
+
+// disconnect closes the channel and the connection, which may already be closed by the broker
+func (r *MessageHandler) disconnect() {
+	r.setState(StateDisconnected)
+
+	if err := r.ch.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+		slog.Error("Error closing channel", "error", err)
+	}
+	if err := r.conn.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+		slog.Error("Error closing connection", "error", err)
+	}
+	slog.Info("Connection closed...")
+}
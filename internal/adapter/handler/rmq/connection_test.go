@@ -0,0 +1,81 @@
+package rmq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang-hexagon/internal/adapter/config"
+	rmqconfig "golang-hexagon/internal/adapter/config/rmq"
+)
+
+func TestBackoff(t *testing.T) {
+	maxDelay := 30 * time.Second
+
+	testCases := []struct {
+		desc  string
+		delay time.Duration
+		next  time.Duration
+	}{
+		{
+			desc:  "Doubled",
+			delay: time.Second,
+			next:  2 * time.Second,
+		},
+		{
+			desc:  "CappedAtMax",
+			delay: 20 * time.Second,
+			next:  maxDelay,
+		},
+		{
+			desc:  "AtMax",
+			delay: maxDelay,
+			next:  maxDelay,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			for i := 0; i < 100; i++ {
+				wait, next := backoff(tc.delay, maxDelay)
+				if wait < tc.delay || wait > tc.delay+tc.delay/2 {
+					t.Fatalf("[case: %s] expected to wait between %v and %v; got %v", tc.desc, tc.delay, tc.delay+tc.delay/2, wait)
+				}
+				if next != tc.next {
+					t.Fatalf("[case: %s] expected the next delay %v; got %v", tc.desc, tc.next, next)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageHandler_Connect(t *testing.T) {
+	// nothing listens on the port, so every attempt is refused until the context is done
+	handler := &MessageHandler{
+		minDelay: 10 * time.Millisecond,
+		maxDelay: 20 * time.Millisecond,
+		conf: &config.Container{
+			RMQ: &rmqconfig.Config{
+				Host:     "127.0.0.1",
+				Port:     "1",
+				User:     "guest",
+				Password: "guest",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := handler.connect(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to get %q; got %v", context.DeadlineExceeded, err)
+	}
+	if state := handler.State(); state != StateDisconnected {
+		t.Errorf("expected the state %q; got %q", StateDisconnected, state)
+	}
+}
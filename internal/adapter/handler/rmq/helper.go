@@ -1,6 +1,9 @@
 package rmq
 
-import "golang-hexagon/internal/core/domain"
+import (
+	"golang-hexagon/internal/core/domain"
+	"time"
+)
 
 // asVal returns a value from pointer
 func asVal[T any](val *T) T {
@@ -19,8 +22,104 @@ func asPtr[T any](val T) *T {
 func toUser(msg *msg) *domain.User {
 	return &domain.User{
 		ID:       asVal(msg.UID),
+		Name:     asVal(msg.Name),
 		Email:    asVal(msg.Email),
 		Password: string(asVal(msg.Password)),
 		Role:     asVal(msg.Role),
+		Version:  asVal(msg.Version),
 	}
 }
+
+// toUserMessage converts the user to the message sent back, without the password hash
+func toUserMessage(user *domain.User) *userMessage {
+	return &userMessage{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Role:       user.Role,
+		VerifiedAt: user.VerifiedAt,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		Version:    user.Version,
+		DeletedAt:  user.DeletedAt,
+	}
+}
+
+// toUserMessages converts the users to the messages sent back, without the password hashes
+func toUserMessages(users []*domain.User) []*userMessage {
+	messages := make([]*userMessage, 0, len(users))
+	for _, user := range users {
+		messages = append(messages, toUserMessage(user))
+	}
+
+	return messages
+}
+
+// toUserProfiles projects the users to their public profiles
+// unless it is the user's own or the account details may be read
+func toUserProfiles(users []*domain.User, payload *domain.TokenPayload, readDetails bool) []any {
+	profiles := make([]any, 0, len(users))
+	for _, user := range users {
+		if readDetails || user.ID == payload.UserID {
+			profiles = append(profiles, toUserMessage(user))
+			continue
+		}
+
+		profiles = append(profiles, &publicUserMessage{
+			ID:        user.ID,
+			Name:      user.Name,
+			CreatedAt: user.CreatedAt,
+		})
+	}
+
+	return profiles
+}
+
+// toRole converts the message to a role, the permissions are parsed from the "resource:action" format
+func toRole(msg *msg) (*domain.Role, error) {
+	role := &domain.Role{
+		ID:          asVal(msg.RoleID),
+		Name:        domain.UserRole(asVal(msg.Name)),
+		Description: asVal(msg.Description),
+	}
+
+	if msg.Permissions == nil {
+		return role, nil
+	}
+
+	role.Permissions = make([]domain.Permission, 0, len(*msg.Permissions))
+	for _, permission := range *msg.Permissions {
+		p, err := domain.ParsePermission(permission)
+		if err != nil {
+			return nil, err
+		}
+
+		role.Permissions = append(role.Permissions, p)
+	}
+
+	return role, nil
+}
+
+func toAuthMessage(tokens *domain.TokenPair) *authMessage {
+	return &authMessage{
+		AccessToken:      string(tokens.AccessToken),
+		ExpiresAt:        tokens.ExpiresAt,
+		ExpiresIn:        int64(time.Until(tokens.ExpiresAt).Seconds()),
+		RefreshToken:     string(tokens.RefreshToken),
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+}
+
+func toMFAChallengeMessage(challenge *domain.MFAChallenge) *mfaChallengeMessage {
+	return &mfaChallengeMessage{
+		MFARequired: true,
+		MFAToken:    string(challenge.Token),
+		ExpiresAt:   challenge.ExpiresAt,
+	}
+}
+
+// loginSource is the source of RMQ logins. The properties of a message, its app id included,
+// are set by the publisher and every client shares the broker connection,
+// so there is nothing to tell clients apart by. The source limit is disabled,
+// failed RMQ logins are only limited per account
+const loginSource = ""
@@ -3,80 +3,272 @@ package rmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/streadway/amqp"
 	"golang-hexagon/internal/adapter/config"
 	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port"
 	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 )
 
 // message types
 const (
-	msgTypeLogin  = "login"
-	msgTypeSignup = "signup"
-	msgTypeUpdate = "update"
-	msgTypeDelete = "delete"
-	msgTypeList   = "list"
+	msgTypeLogin          = "login"
+	msgTypeVerifyMFA      = "verify_mfa"
+	msgTypeRefresh        = "refresh"
+	msgTypeLogout         = "logout"
+	msgTypeSignup         = "signup"
+	msgTypeForgotPassword = "forgot_password"
+	msgTypeResetPassword  = "reset_password"
+	msgTypeVerifyEmail    = "verify_email"
+	msgTypeResendVerify   = "resend_verification"
+	msgTypeEnrollTOTP     = "enroll_totp"
+	msgTypeConfirmTOTP    = "confirm_totp"
+	msgTypeDisableTOTP    = "disable_totp"
+	msgTypeRecoveryCodes  = "regenerate_recovery_codes"
+	msgTypeUpdate         = "update"
+	msgTypeDelete         = "delete"
+	msgTypeList           = "list"
+	msgTypeListDeleted    = "list_deleted"
+	msgTypeRestore        = "restore"
+	msgTypePurge          = "purge"
+	msgTypeGetMe          = "get_me"
+	msgTypeUpdateMe       = "update_me"
+	msgTypeDeleteMe       = "delete_me"
+	msgTypeListRoles      = "list_roles"
+	msgTypeGetRole        = "get_role"
+	msgTypeCreateRole     = "create_role"
+	msgTypeUpdateRole     = "update_role"
+	msgTypeDeleteRole     = "delete_role"
+	msgTypePermissions    = "list_permissions"
 )
 
-const connFormat = "amqp://%s:%s@%s:%s/%s"
+// publicMsgTypes are the message types that are handled without an access token,
+// since they are sent before the user has one or carry a token of their own
+var publicMsgTypes = map[string]struct{}{
+	msgTypeLogin:          {},
+	msgTypeVerifyMFA:      {},
+	msgTypeRefresh:        {},
+	msgTypeSignup:         {},
+	msgTypeForgotPassword: {},
+	msgTypeResetPassword:  {},
+	msgTypeVerifyEmail:    {},
+	msgTypeResendVerify:   {},
+}
+
+// idempotentMsgTypes are the message types that only read, so handling them again after a transient failure
+// has no effect besides the response. Other messages may have been applied before the failure and are not retried
+var idempotentMsgTypes = map[string]struct{}{
+	msgTypeList:        {},
+	msgTypeListDeleted: {},
+	msgTypeGetMe:       {},
+	msgTypeListRoles:   {},
+	msgTypeGetRole:     {},
+	msgTypePermissions: {},
+}
+
+var (
+	// errDeliveriesClosed is returned by Consume when the broker closes the deliveries channel
+	errDeliveriesClosed = errors.New("deliveries channel closed by the broker")
+	// errMalformedMessage is an error for when the body of a message is not a valid message
+	errMalformedMessage = errors.New("malformed message")
+	// errUnknownMsgType is an error for when the type of a message is not handled
+	errUnknownMsgType = errors.New("unknown message type")
+)
 
 // MessageHandler is a RabbitMQ message service
 type (
 	MessageHandler struct {
-		authSvc port.AuthService
-		userSvc port.UserService
-		conf    *config.Container
-		conn    *amqp.Connection
-		ch      *amqp.Channel
+		authSvc        port.AuthService
+		authorizer     port.Authorizer
+		userSvc        port.UserService
+		passwordSvc    port.PasswordService
+		verifySvc      port.VerificationService
+		mfaSvc         port.MFAService
+		roleSvc        port.RoleService
+		requireMFA     bool
+		prefetch       int
+		workers        int
+		msgTimeout     time.Duration
+		minDelay       time.Duration
+		maxDelay       time.Duration
+		maxAttempts    int64
+		retryDelay     time.Duration
+		topology       *topology
+		passive        bool
+		requireVersion bool
+		conf           *config.Container
+		conn           *amqp.Connection
+		ch             *amqp.Channel
+		stateMu        sync.RWMutex
+		state          ConnectionState
 	}
 
 	msg struct {
-		Type     string           `json:"type"`
-		Name     *string          `json:"name"`
-		Email    *string          `json:"email"`
-		Password *[]byte          `json:"password"`
-		Role     *domain.UserRole `json:"role"`
-		UID      *uint64          `json:"uid"`
-		Token    *string          `json:"token"`
-		Offset   *uint64          `json:"offset"`
-		Limit    *uint64          `json:"limit"`
+		Type         string           `json:"type"`
+		Name         *string          `json:"name"`
+		Email        *string          `json:"email"`
+		Password     *[]byte          `json:"password"`
+		CurrentPass  *[]byte          `json:"current_password"`
+		Role         *domain.UserRole `json:"role"`
+		UID          *uint64          `json:"uid"`
+		Token        *string          `json:"token"`
+		RefreshToken *string          `json:"refresh_token"`
+		ResetToken   *string          `json:"reset_token"`
+		VerifyToken  *string          `json:"verification_token"`
+		MFAToken     *string          `json:"mfa_token"`
+		Code         *string          `json:"code"`
+		RoleID       *uint64          `json:"role_id"`
+		Description  *string          `json:"description"`
+		Permissions  *[]string        `json:"permissions"`
+		Offset       *uint64          `json:"offset"`
+		Limit        *uint64          `json:"limit"`
+		Version      *uint64          `json:"version"`
 	}
 )
 
-// New creates a new RabbitMQ message service
-func New(conf *config.Container, authSvc port.AuthService, userSvc port.UserService) *MessageHandler {
-	connection, err := amqp.Dial(fmt.Sprintf(connFormat, conf.RMQ.User, conf.RMQ.Password, conf.RMQ.Host, conf.RMQ.Port, conf.RMQ.Vhost))
+// New creates a new RabbitMQ message service.
+// It does not connect to the broker, the connection is established and supervised by Consume
+func New(
+	conf *config.Container,
+	authSvc port.AuthService,
+	authorizer port.Authorizer,
+	userSvc port.UserService,
+	passwordSvc port.PasswordService,
+	verifySvc port.VerificationService,
+	mfaSvc port.MFAService,
+	roleSvc port.RoleService,
+) (*MessageHandler, error) {
+	requireMFA, err := strconv.ParseBool(conf.Auth.MFARequiredForAdmins)
 	if err != nil {
-		slog.Error("Error connecting to RabbitMQ instance", err)
-		panic(err)
+		return nil, err
 	}
-	slog.Info("Successfully connected to RabbitMQ instance!")
 
-	channel, err := connection.Channel()
+	prefetch, err := strconv.Atoi(conf.RMQ.Prefetch)
 	if err != nil {
-		slog.Error("Error opening channel", err)
-		panic(err)
+		return nil, err
 	}
 
-	return &MessageHandler{
-		authSvc: authSvc,
-		userSvc: userSvc,
-		conf:    conf,
-		conn:    connection,
-		ch:      channel,
+	workers, err := strconv.Atoi(conf.RMQ.Workers)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		return nil, fmt.Errorf("invalid number of workers %d", workers)
+	}
+
+	msgTimeout, err := time.ParseDuration(conf.RMQ.MessageTimeout)
+	if err != nil {
+		return nil, err
 	}
+
+	reconnectMinDelay, err := time.ParseDuration(conf.RMQ.ReconnectMinDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	reconnectMaxDelay, err := time.ParseDuration(conf.RMQ.ReconnectMaxDelay)
+	if err != nil {
+		return nil, err
+	}
+	maxAttempts, err := strconv.ParseInt(conf.RMQ.MaxAttempts, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if maxAttempts < 1 {
+		return nil, fmt.Errorf("invalid number of attempts %d", maxAttempts)
+	}
+
+	retryDelay, err := time.ParseDuration(conf.RMQ.RetryDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	passive, err := strconv.ParseBool(conf.RMQ.TopologyPassive)
+	if err != nil {
+		return nil, err
+	}
+
+	requireVersion, err := strconv.ParseBool(conf.RMQ.RequireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := loadTopology(conf.RMQ, retryDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	if reconnectMinDelay <= 0 || reconnectMaxDelay < reconnectMinDelay {
+		return nil, fmt.Errorf("invalid reconnect delays %s and %s", reconnectMinDelay, reconnectMaxDelay)
+	}
+
+	return &MessageHandler{
+		authSvc:        authSvc,
+		authorizer:     authorizer,
+		userSvc:        userSvc,
+		passwordSvc:    passwordSvc,
+		verifySvc:      verifySvc,
+		mfaSvc:         mfaSvc,
+		roleSvc:        roleSvc,
+		requireMFA:     requireMFA,
+		prefetch:       prefetch,
+		workers:        workers,
+		msgTimeout:     msgTimeout,
+		minDelay:       reconnectMinDelay,
+		maxDelay:       reconnectMaxDelay,
+		maxAttempts:    maxAttempts,
+		retryDelay:     retryDelay,
+		topology:       topology,
+		passive:        passive,
+		requireVersion: requireVersion,
+		state:          StateDisconnected,
+		conf:           conf,
+	}, nil
 }
 
-// Consume consumes messages from queue
-func (r *MessageHandler) Consume(ctx context.Context) {
-	defer func() {
-		if err := r.conn.Close(); err != nil {
-			slog.Error("Error closing connection", err)
+// Consume consumes messages from the queue with a pool of workers until the context is done.
+// It connects to the broker and reconnects with backoff whenever the connection or the channel is lost.
+// It blocks until the messages in flight are processed and the connection is closed
+func (r *MessageHandler) Consume(ctx context.Context) error {
+	for {
+		err := r.connect(ctx)
+		if err != nil {
+			// the context is done before a connection could be established
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		err = r.consume(ctx)
+		r.disconnect()
+
+		if ctx.Err() != nil {
+			return err
 		}
-		slog.Info("Config Connection closed...")
-	}()
+
+		slog.Error("Lost connection to RabbitMQ instance, reconnecting...", "error", err)
+	}
+}
+
+// consume consumes messages over the open channel until the context is done or the deliveries channel is closed
+func (r *MessageHandler) consume(ctx context.Context) error {
+	closed := r.ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	// limiting unacknowledged deliveries so they are spread between consumers
+	err := r.ch.Qos(
+		r.prefetch, // prefetch count
+		0,          // prefetch size
+		false,      // global
+	)
+	if err != nil {
+		return err
+	}
 
 	// declaring consumer with its properties over channel opened
 	msgs, err := r.ch.Consume(
@@ -89,94 +281,390 @@ func (r *MessageHandler) Consume(ctx context.Context) {
 		nil,                    //args
 	)
 	if err != nil {
-		slog.Error("Error consuming messages", err)
-		panic(err)
+		return err
 	}
-	gorutineCtx, cancel := context.WithCancel(ctx)
 
-	go func(ctx context.Context) {
-		select {
-		case msg := <-msgs:
-			r.processMessage(msg)
-
-		case <-ctx.Done():
-			if err := r.ch.Cancel(r.conf.RMQ.ConsumerTag, true); err != nil {
-				slog.Error("Error canceling consumer", err)
+	// workers process deliveries until the deliveries channel is closed
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range msgs {
+				r.processMessage(ctx, delivery)
 			}
-			slog.Info("Consumer cancelled. Stopping...")
-			return
-		}
-	}(gorutineCtx)
+		}()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	slog.Info("Consuming messages", "queue", r.conf.RMQ.InQueue, "workers", r.workers, "prefetch", r.prefetch)
 
 	select {
+	case <-stopped:
+		select {
+		case amqpErr := <-closed:
+			if amqpErr != nil {
+				return amqpErr
+			}
+		default:
+		}
+		return errDeliveriesClosed
 	case <-ctx.Done():
-		cancel()
-		if err := r.ch.Close(); err != nil {
-			slog.Error("Error closing channel", err)
+	}
+
+	// cancelling the consumer closes the deliveries channel after the buffered deliveries are handed out
+	if err := r.ch.Cancel(r.conf.RMQ.ConsumerTag, false); err != nil {
+		slog.Error("Error canceling consumer", "error", err)
+	}
+	slog.Info("Consumer cancelled. Draining messages in flight...")
+
+	<-stopped
+
+	return nil
+}
+
+// processMessage processes message within its own timeout.
+// The timeout is not bound to the cancellation of the consumer, so messages in flight are finished on shutdown.
+// Malformed messages and messages of unknown types are rejected with a bad request response and dead-lettered,
+// malformed messages are only answered if they name the queue to reply to.
+// Transient failures of idempotent messages are retried until the attempts are used up,
+// transient failures of other messages are dead-lettered since they may have been applied
+func (r *MessageHandler) processMessage(ctx context.Context, delivery amqp.Delivery) {
+	var m msg
+	if err := json.Unmarshal(delivery.Body, &m); err != nil {
+		if delivery.ReplyTo != "" {
+			if sendErr := r.sendMessage(&delivery, newResponseMessage("", errMalformedMessage)); sendErr != nil {
+				slog.Error("Error sending message", "error", sendErr)
+			}
 		}
-		slog.Info("Channel closed. Stopping...")
-	default: // do nothing
+		r.deadLetter(&delivery, fmt.Errorf("%w: %w", errMalformedMessage, err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.msgTimeout)
+	defer cancel()
+
+	message, err := r.handle(ctx, &m, &delivery)
+
+	_, idempotent := idempotentMsgTypes[m.Type]
+	if err != nil && idempotent && isTransient(err) && r.retry(&delivery, err) {
+		return
+	}
+
+	if sendErr := r.sendMessage(&delivery, newResponseMessage(string(message), err)); sendErr != nil {
+		slog.Error("Error sending message", "error", sendErr)
+	}
+
+	// the client got the error response, the message is kept for inspection
+	if err != nil && (isTransient(err) || errors.Is(err, errUnknownMsgType)) {
+		r.deadLetter(&delivery, err)
+		return
+	}
+
+	if err = delivery.Ack(false); err != nil {
+		slog.Error("Error acknowledging message", "error", err)
 	}
 }
 
-// processMessage processes message
-func (r *MessageHandler) processMessage(delivery amqp.Delivery) {
+// handle authenticates the message unless its type is public,
+// applies the admin checks and dispatches it to the service of its type
+func (r *MessageHandler) handle(ctx context.Context, m *msg, delivery *amqp.Delivery) ([]byte, error) {
 	var (
-		m       msg
 		message []byte
 		err     error
 		u       *domain.User
 		us      []*domain.User
+		tp      *domain.TokenPair
+		c       *domain.MFAChallenge
+		p       *domain.TokenPayload
+		e       *domain.TOTPEnrollment
+		codes   []string
+		rl      *domain.Role
+		rls     []*domain.Role
+		perms   []domain.Permission
+		details bool
 	)
-	if err = json.Unmarshal(delivery.Body, &m); err != nil {
-		slog.Error("Error unmarshalling delivery", err)
-		return
+
+	if _, public := publicMsgTypes[m.Type]; !public {
+		p, err = r.authenticate(ctx, m)
+		if err != nil {
+			return nil, err
+		}
 	}
-	ctx := context.Background()
+
 	switch m.Type {
 	case msgTypeLogin:
-		message, err = r.authSvc.Login(ctx, asVal(m.Email), string(asVal(m.Password)))
+		tp, c, err = r.authSvc.Login(ctx, asVal(m.Email), string(asVal(m.Password)), loginSource)
+		if tp != nil {
+			message, _ = json.Marshal(toAuthMessage(tp))
+		}
+		if c != nil {
+			message, _ = json.Marshal(toMFAChallengeMessage(c))
+		}
+	case msgTypeVerifyMFA:
+		tp, err = r.authSvc.VerifyMFA(ctx, []byte(asVal(m.MFAToken)), asVal(m.Code), loginSource)
+		if tp != nil {
+			message, _ = json.Marshal(toAuthMessage(tp))
+		}
+	case msgTypeRefresh:
+		tp, err = r.authSvc.Refresh(ctx, []byte(asVal(m.RefreshToken)))
+		if tp != nil {
+			message, _ = json.Marshal(toAuthMessage(tp))
+		}
+	case msgTypeLogout:
+		err = r.authSvc.Logout(ctx, p, []byte(asVal(m.RefreshToken)))
+	case msgTypeForgotPassword:
+		err = r.passwordSvc.ForgotPassword(ctx, asVal(m.Email))
+	case msgTypeResetPassword:
+		err = r.passwordSvc.ResetPassword(ctx, []byte(asVal(m.ResetToken)), string(asVal(m.Password)))
+	case msgTypeVerifyEmail:
+		err = r.verifySvc.VerifyEmail(ctx, []byte(asVal(m.VerifyToken)))
+	case msgTypeResendVerify:
+		err = r.verifySvc.ResendVerification(ctx, asVal(m.Email))
+	case msgTypeEnrollTOTP:
+		e, err = r.mfaSvc.EnrollTOTP(ctx, p.UserID)
+		if e != nil {
+			message, _ = json.Marshal(e)
+		}
+	case msgTypeConfirmTOTP:
+		codes, err = r.mfaSvc.ConfirmTOTP(ctx, p.UserID, asVal(m.Code))
+		if codes != nil {
+			message, _ = json.Marshal(codes)
+		}
+	case msgTypeDisableTOTP:
+		err = r.mfaSvc.DisableTOTP(ctx, p.UserID, asVal(m.Code))
+	case msgTypeRecoveryCodes:
+		codes, err = r.mfaSvc.RegenerateRecoveryCodes(ctx, p.UserID, asVal(m.Code))
+		if codes != nil {
+			message, _ = json.Marshal(codes)
+		}
 	case msgTypeSignup:
-		user := toUser(&m)
+		user := toUser(m)
 		u, err = r.userSvc.Register(ctx, user)
 		if u != nil {
-			message, _ = json.Marshal(u)
+			message, _ = json.Marshal(toUserMessage(u))
 		}
 	case msgTypeUpdate:
-		user := toUser(&m)
-		u, err = r.userSvc.UpdateUser(ctx, user)
+		err = r.authorize(ctx, p, domain.ActionUpdate, domain.ResourceUsers)
+		if err == nil && asVal(m.UID) == p.UserID && asVal(m.Role) != "" {
+			err = domain.ErrSelfRoleChange
+		}
+		if err == nil && asVal(m.Role) != "" {
+			err = r.authorizeAssign(ctx, p, asVal(m.Role))
+		}
+		if err == nil {
+			err = r.checkVersion(m)
+		}
+		if err == nil {
+			u, err = r.userSvc.UpdateUser(ctx, toUser(m))
+		}
 		if u != nil {
-			message, _ = json.Marshal(u)
+			message, _ = json.Marshal(toUserMessage(u))
 		}
 	case msgTypeDelete:
-		err = r.userSvc.DeleteUser(ctx, asVal(m.UID))
+		err = r.authorize(ctx, p, domain.ActionDelete, domain.ResourceUsers)
+		if err == nil {
+			err = r.checkVersion(m)
+		}
+		if err == nil {
+			err = r.userSvc.DeleteUser(ctx, asVal(m.UID), asVal(m.Version))
+		}
 	case msgTypeList:
-		us, err = r.userSvc.ListUsers(ctx, asVal(m.Offset), asVal(m.Limit))
+		err = r.authorize(ctx, p, domain.ActionRead, domain.ResourceUsers)
+		if err == nil {
+			details, err = r.authorizer.Can(ctx, p, domain.ActionReadDetails, domain.ResourceUsers)
+		}
+		if err == nil {
+			us, err = r.userSvc.ListUsers(ctx, asVal(m.Offset), asVal(m.Limit))
+		}
 		if us != nil {
-			message, _ = json.Marshal(us)
+			message, _ = json.Marshal(toUserProfiles(us, p, details))
+		}
+	case msgTypeListDeleted:
+		err = r.authorize(ctx, p, domain.ActionRestore, domain.ResourceUsers)
+		if err == nil {
+			us, err = r.userSvc.ListDeletedUsers(ctx, asVal(m.Offset), asVal(m.Limit))
+		}
+		if us != nil {
+			message, _ = json.Marshal(toUserMessages(us))
+		}
+	case msgTypeRestore:
+		err = r.authorize(ctx, p, domain.ActionRestore, domain.ResourceUsers)
+		if err == nil {
+			u, err = r.userSvc.RestoreUser(ctx, asVal(m.UID))
+		}
+		if u != nil {
+			message, _ = json.Marshal(toUserMessage(u))
+		}
+	case msgTypePurge:
+		err = r.authorize(ctx, p, domain.ActionPurge, domain.ResourceUsers)
+		if err == nil {
+			err = r.userSvc.PurgeUser(ctx, asVal(m.UID))
+		}
+	case msgTypeGetMe:
+		u, err = r.userSvc.GetUser(ctx, p.UserID)
+		if u != nil {
+			message, _ = json.Marshal(toUserMessage(u))
+		}
+	case msgTypeUpdateMe:
+		user := toUser(m)
+		user.ID = p.UserID
+		u, err = r.userSvc.UpdateProfile(ctx, p, user, string(asVal(m.CurrentPass)))
+		if u != nil {
+			message, _ = json.Marshal(toUserMessage(u))
+		}
+	case msgTypeDeleteMe:
+		err = r.userSvc.DeleteUser(ctx, p.UserID, 0)
+	case msgTypeListRoles:
+		err = r.authorize(ctx, p, domain.ActionRead, domain.ResourceRoles)
+		if err == nil {
+			rls, err = r.roleSvc.ListRoles(ctx, asVal(m.Offset), asVal(m.Limit))
+		}
+		if rls != nil {
+			message, _ = json.Marshal(rls)
+		}
+	case msgTypeGetRole:
+		err = r.authorize(ctx, p, domain.ActionRead, domain.ResourceRoles)
+		if err == nil {
+			rl, err = r.roleSvc.GetRole(ctx, asVal(m.RoleID))
+		}
+		if rl != nil {
+			message, _ = json.Marshal(rl)
+		}
+	case msgTypeCreateRole:
+		err = r.authorize(ctx, p, domain.ActionCreate, domain.ResourceRoles)
+		if err == nil {
+			rl, err = toRole(m)
+		}
+		if err == nil {
+			err = r.authorizeGrant(ctx, p, rl.Permissions)
+		}
+		if err == nil {
+			rl, err = r.roleSvc.CreateRole(ctx, rl)
+		}
+		if rl != nil {
+			message, _ = json.Marshal(rl)
+		}
+	case msgTypeUpdateRole:
+		err = r.authorize(ctx, p, domain.ActionUpdate, domain.ResourceRoles)
+		if err == nil {
+			rl, err = toRole(m)
 		}
+		if err == nil {
+			err = r.authorizeGrant(ctx, p, rl.Permissions)
+		}
+		if err == nil {
+			rl, err = r.roleSvc.UpdateRole(ctx, rl)
+		}
+		if rl != nil {
+			message, _ = json.Marshal(rl)
+		}
+	case msgTypeDeleteRole:
+		err = r.authorize(ctx, p, domain.ActionDelete, domain.ResourceRoles)
+		if err == nil {
+			err = r.roleSvc.DeleteRole(ctx, asVal(m.RoleID))
+		}
+	case msgTypePermissions:
+		err = r.authorize(ctx, p, domain.ActionRead, domain.ResourceRoles)
+		if err == nil {
+			perms, err = r.roleSvc.ListPermissions(ctx)
+		}
+		if perms != nil {
+			message, _ = json.Marshal(perms)
+		}
+	default:
+		err = errUnknownMsgType
 	}
 
-	if err = r.sendMessage(newResponseMessage(string(message), err)); err != nil {
-		slog.Error("Error sending message", err)
+	return message, err
+}
+
+// authenticate verifies the access token of the message
+func (r *MessageHandler) authenticate(ctx context.Context, m *msg) (*domain.TokenPayload, error) {
+	token := asVal(m.Token)
+	if token == "" {
+		return nil, domain.ErrUnauthorized
 	}
-	if err = delivery.Ack(false); err != nil {
-		slog.Error("Error acknowledging message", err)
+
+	return r.authSvc.VerifyToken(ctx, []byte(token))
+}
+
+// authorize checks that the role of the authenticated user grants the permission to perform the action on the resource,
+// and that the access token is issued after the second factor was passed if the permission requires it
+func (r *MessageHandler) authorize(ctx context.Context, payload *domain.TokenPayload, action domain.Action, resource domain.Resource) error {
+	allowed, err := r.authorizer.Can(ctx, payload, action, resource)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return domain.ErrForbidden
+	}
+
+	permission := domain.Permission{Resource: resource, Action: action}
+	if r.requireMFA && permission.RequiresMFA() && !payload.MFA() {
+		return domain.ErrMFARequired
 	}
+
+	return nil
 }
 
-// sendMessage sends message back to responses queue
-func (r *MessageHandler) sendMessage(msg *amqp.Publishing) error {
-	err := r.ch.Publish(
-		r.conf.RMQ.OutExchange,   // exchange
-		r.conf.RMQ.OutRoutingKey, // routing key
-		false,                    // mandatory
-		false,                    // immediate
-		asVal(msg),               // message
-	)
+// authorizeAssign checks that the authenticated user holds every permission of the role it assigns
+func (r *MessageHandler) authorizeAssign(ctx context.Context, payload *domain.TokenPayload, role domain.UserRole) error {
+	allowed, err := r.authorizer.CanAssign(ctx, payload, role)
 	if err != nil {
 		return err
-	} else {
-		return nil
 	}
+
+	if !allowed {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// authorizeGrant checks that the authenticated user holds every one of the permissions it grants to a role
+func (r *MessageHandler) authorizeGrant(ctx context.Context, payload *domain.TokenPayload, permissions []domain.Permission) error {
+	allowed, err := r.authorizer.CanGrant(ctx, payload, permissions)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+// checkVersion rejects an update or delete of a user without the version it was read at, if the version is required
+func (r *MessageHandler) checkVersion(m *msg) error {
+	if r.requireVersion && asVal(m.Version) == 0 {
+		return domain.ErrVersionRequired
+	}
+
+	return nil
+}
+
+// sendMessage sends the response to the reply_to queue of the delivery with its correlation_id,
+// or to the configured responses exchange if the delivery has no reply_to
+func (r *MessageHandler) sendMessage(delivery *amqp.Delivery, msg *amqp.Publishing) error {
+	exchange, routingKey := r.conf.RMQ.OutExchange, r.conf.RMQ.OutRoutingKey
+	if delivery.ReplyTo != "" {
+		exchange, routingKey = "", delivery.ReplyTo
+	}
+	msg.CorrelationId = delivery.CorrelationId
+
+	return r.ch.Publish(
+		exchange,   // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		asVal(msg), // message
+	)
 }
@@ -2,19 +2,66 @@ package rmq
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/streadway/amqp"
 	"golang-hexagon/internal/core/domain"
 	"net/http"
+	"time"
 )
 
 const contentType = "application/json"
 
 type (
 	ResponseMessage struct {
-		Success bool   `json:"success"`
-		Status  int    `json:"statusCode"`
+		Success    bool               `json:"success"`
+		Status     int                `json:"statusCode"`
+		Message    string             `json:"message"`
+		Error      string             `json:"error"`
+		Violations []violationMessage `json:"violations,omitempty"`
+	}
+
+	// violationMessage represents a broken password policy rule
+	violationMessage struct {
+		Rule    string `json:"rule"`
 		Message string `json:"message"`
-		Error   string `json:"error"`
+	}
+
+	// authMessage represents the token pair sent back on login and refresh
+	authMessage struct {
+		AccessToken      string    `json:"token"`
+		ExpiresAt        time.Time `json:"expires_at"`
+		ExpiresIn        int64     `json:"expires_in"`
+		RefreshToken     string    `json:"refresh_token"`
+		RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	}
+
+	// userMessage represents the user sent back to the user itself and to users with access to account details.
+	// It leaves out the password hash, its fields are encoded with the same keys as domain.User
+	userMessage struct {
+		ID         uint64
+		Name       string
+		Email      string
+		Role       domain.UserRole
+		VerifiedAt *time.Time
+		CreatedAt  time.Time
+		UpdatedAt  time.Time
+		Version    uint64
+		DeletedAt  *time.Time
+	}
+
+	// publicUserMessage represents the public profile of a user sent to users without access to account details,
+	// its fields are encoded with the same keys as the full user
+	publicUserMessage struct {
+		ID        uint64
+		Name      string
+		CreatedAt time.Time
+	}
+
+	// mfaChallengeMessage represents the MFA challenge sent back on login of a user with enabled MFA
+	mfaChallengeMessage struct {
+		MFARequired bool      `json:"mfa_required"`
+		MFAToken    string    `json:"mfa_token"`
+		ExpiresAt   time.Time `json:"expires_at"`
 	}
 )
 
@@ -24,12 +71,39 @@ var errorStatusMap = map[error]int{
 	domain.ErrDataNotFound:               http.StatusNotFound,
 	domain.ErrConflictingData:            http.StatusConflict,
 	domain.ErrInvalidCredentials:         http.StatusUnauthorized,
+	domain.ErrExpiredToken:               http.StatusUnauthorized,
+	domain.ErrInvalidToken:               http.StatusUnauthorized,
+	domain.ErrRevokedToken:               http.StatusUnauthorized,
+	domain.ErrInvalidRefreshToken:        http.StatusUnauthorized,
+	domain.ErrRefreshTokenReused:         http.StatusUnauthorized,
 	domain.ErrUnauthorized:               http.StatusUnauthorized,
 	domain.ErrEmptyAuthorizationHeader:   http.StatusUnauthorized,
 	domain.ErrInvalidAuthorizationHeader: http.StatusUnauthorized,
 	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
 	domain.ErrForbidden:                  http.StatusForbidden,
+	domain.ErrAccountLocked:              http.StatusLocked,
+	domain.ErrTooManyLoginAttempts:       http.StatusTooManyRequests,
 	domain.ErrNoUpdatedData:              http.StatusBadRequest,
+	domain.ErrInvalidResetToken:          http.StatusBadRequest,
+	domain.ErrInvalidVerificationToken:   http.StatusBadRequest,
+	domain.ErrVerificationRateLimited:    http.StatusTooManyRequests,
+	domain.ErrEmailNotVerified:           http.StatusForbidden,
+	domain.ErrWeakPassword:               http.StatusBadRequest,
+	domain.ErrInvalidMFAToken:            http.StatusUnauthorized,
+	domain.ErrInvalidMFACode:             http.StatusUnauthorized,
+	domain.ErrMFAAlreadyEnabled:          http.StatusConflict,
+	domain.ErrMFANotEnabled:              http.StatusBadRequest,
+	domain.ErrMFARequired:                http.StatusForbidden,
+	domain.ErrInvalidCurrentPassword:     http.StatusForbidden,
+	domain.ErrVersionMismatch:            http.StatusPreconditionFailed,
+	domain.ErrVersionRequired:            http.StatusPreconditionRequired,
+	domain.ErrSelfRoleChange:             http.StatusForbidden,
+	domain.ErrUnknownRole:                http.StatusBadRequest,
+	domain.ErrUnknownPermission:          http.StatusBadRequest,
+	domain.ErrRoleInUse:                  http.StatusConflict,
+	domain.ErrBuiltInRole:                http.StatusConflict,
+	errMalformedMessage:                  http.StatusBadRequest,
+	errUnknownMsgType:                    http.StatusBadRequest,
 }
 
 // newResponseMessage creates a new response message for RMQ sending
@@ -38,22 +112,34 @@ func newResponseMessage(message string, err error) *amqp.Publishing {
 		statusCode int
 		ok         bool
 		errMsg     string
+		violations []violationMessage
+		policyErr  *domain.PasswordPolicyError
 	)
 
 	if err != nil {
+		errMsg = err.Error()
+		if errors.As(err, &policyErr) {
+			for _, violation := range policyErr.Violations {
+				violations = append(violations, violationMessage{
+					Rule:    string(violation.Rule),
+					Message: violation.Message,
+				})
+			}
+			err = domain.ErrWeakPassword
+		}
 		statusCode, ok = errorStatusMap[err]
 		if !ok {
 			statusCode = http.StatusInternalServerError
 		}
-		errMsg = err.Error()
 	} else {
 		statusCode = http.StatusOK
 	}
 	rsp := ResponseMessage{
-		Success: statusCode == http.StatusOK,
-		Status:  statusCode,
-		Message: message,
-		Error:   errMsg,
+		Success:    statusCode == http.StatusOK,
+		Status:     statusCode,
+		Message:    message,
+		Error:      errMsg,
+		Violations: violations,
 	}
 	// error is muted here because we know that there will be no encoding errors
 	body, _ := json.Marshal(rsp)
@@ -0,0 +1,158 @@
+package rmq
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/streadway/amqp"
+	"golang-hexagon/internal/core/domain"
+)
+
+// headers set on retried and dead-lettered messages
+const (
+	headerRetryCount         = "x-retry-count"
+	headerFailureReason      = "x-failure-reason"
+	headerFailedAt           = "x-failed-at"
+	headerOriginalExchange   = "x-original-exchange"
+	headerOriginalRoutingKey = "x-original-routing-key"
+)
+
+// isTransient reports whether the failure may not occur again when the message is retried.
+// Errors with a defined status are part of the response to the client and are not retried
+func isTransient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, domain.ErrInternal) {
+		return true
+	}
+
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return false
+	}
+
+	_, ok := errorStatusMap[err]
+	return !ok
+}
+
+// retryCount returns the number of times the message has been retried
+func retryCount(delivery *amqp.Delivery) int64 {
+	switch count := delivery.Headers[headerRetryCount].(type) {
+	case int32:
+		return int64(count)
+	case int64:
+		return count
+	default:
+		return 0
+	}
+}
+
+// retry schedules the message for another attempt after a transient failure and acknowledges it.
+// The message is published to the retry exchange, whose queue dead-letters it back to the requests queue
+// after the retry delay. Without a retry exchange the message is requeued once.
+// It returns false if the message has used up its attempts
+func (r *MessageHandler) retry(delivery *amqp.Delivery, cause error) bool {
+	count := retryCount(delivery)
+	if count+1 >= r.maxAttempts {
+		return false
+	}
+
+	if r.conf.RMQ.RetryExchange == "" {
+		if delivery.Redelivered {
+			return false
+		}
+
+		if err := delivery.Nack(false, true); err != nil {
+			slog.Error("Error requeueing message", "error", err)
+		}
+		return true
+	}
+
+	msg := failedMessage(delivery, count+1, cause)
+	err := r.ch.Publish(
+		r.conf.RMQ.RetryExchange, // exchange
+		r.conf.RMQ.InQueue,       // routing key
+		false,                    // mandatory
+		false,                    // immediate
+		msg,                      // message
+	)
+	if err != nil {
+		slog.Error("Error publishing message for retry", "error", err)
+		if err = delivery.Nack(false, true); err != nil {
+			slog.Error("Error requeueing message", "error", err)
+		}
+		return true
+	}
+
+	slog.Warn("Message failed, retrying", "error", cause, "retry", count+1, "delay", r.retryDelay)
+
+	if err = delivery.Ack(false); err != nil {
+		slog.Error("Error acknowledging message", "error", err)
+	}
+
+	return true
+}
+
+// deadLetter preserves the message that can not be processed in the dead-letter exchange
+// with the failure reason in its headers, and acknowledges it.
+// Without a dead-letter exchange the message is rejected and dropped
+func (r *MessageHandler) deadLetter(delivery *amqp.Delivery, cause error) {
+	slog.Error("Message failed permanently, dead-lettering", "error", cause, "retries", retryCount(delivery))
+
+	if r.conf.RMQ.DeadLetterExchange == "" {
+		if err := delivery.Reject(false); err != nil {
+			slog.Error("Error rejecting message", "error", err)
+		}
+		return
+	}
+
+	msg := failedMessage(delivery, retryCount(delivery), cause)
+	err := r.ch.Publish(
+		r.conf.RMQ.DeadLetterExchange, // exchange
+		r.conf.RMQ.InQueue,            // routing key
+		false,                         // mandatory
+		false,                         // immediate
+		msg,                           // message
+	)
+	if err != nil {
+		// the message is kept in the requests queue rather than lost
+		slog.Error("Error publishing message to dead-letter exchange", "error", err)
+		if err = delivery.Nack(false, true); err != nil {
+			slog.Error("Error requeueing message", "error", err)
+		}
+		return
+	}
+
+	if err = delivery.Ack(false); err != nil {
+		slog.Error("Error acknowledging message", "error", err)
+	}
+}
+
+// failedMessage copies the delivery into a new message with the retry count and the failure in its headers
+func failedMessage(delivery *amqp.Delivery, count int64, cause error) amqp.Publishing {
+	headers := amqp.Table{}
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+
+	headers[headerRetryCount] = count
+	headers[headerFailureReason] = cause.Error()
+	headers[headerFailedAt] = time.Now().UTC().Format(time.RFC3339)
+	if _, ok := headers[headerOriginalExchange]; !ok {
+		headers[headerOriginalExchange] = delivery.Exchange
+		headers[headerOriginalRoutingKey] = delivery.RoutingKey
+	}
+
+	return amqp.Publishing{
+		Headers:       headers,
+		ContentType:   delivery.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: delivery.CorrelationId,
+		ReplyTo:       delivery.ReplyTo,
+		MessageId:     delivery.MessageId,
+		Timestamp:     delivery.Timestamp,
+		Type:          delivery.Type,
+		AppId:         delivery.AppId,
+		Body:          delivery.Body,
+	}
+}
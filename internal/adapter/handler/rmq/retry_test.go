@@ -0,0 +1,226 @@
+package rmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"golang-hexagon/internal/core/domain"
+)
+
+func TestIsTransient(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		err       error
+		transient bool
+	}{
+		{
+			desc:      "DeadlineExceeded",
+			err:       context.DeadlineExceeded,
+			transient: true,
+		},
+		{
+			desc:      "WrappedDeadlineExceeded",
+			err:       fmt.Errorf("query: %w", context.DeadlineExceeded),
+			transient: true,
+		},
+		{
+			desc:      "Internal",
+			err:       domain.ErrInternal,
+			transient: true,
+		},
+		{
+			desc:      "Unmapped",
+			err:       errors.New("connection reset by peer"),
+			transient: true,
+		},
+		{
+			desc:      "NotFound",
+			err:       domain.ErrDataNotFound,
+			transient: false,
+		},
+		{
+			desc:      "InvalidCredentials",
+			err:       domain.ErrInvalidCredentials,
+			transient: false,
+		},
+		{
+			desc:      "VersionMismatch",
+			err:       domain.ErrVersionMismatch,
+			transient: false,
+		},
+		{
+			desc:      "MalformedMessage",
+			err:       errMalformedMessage,
+			transient: false,
+		},
+		{
+			desc:      "UnknownMessageType",
+			err:       errUnknownMsgType,
+			transient: false,
+		},
+		{
+			desc: "PasswordPolicy",
+			err: &domain.PasswordPolicyError{
+				Violations: []domain.PasswordViolation{{Message: "too short"}},
+			},
+			transient: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			transient := isTransient(tc.err)
+			if transient != tc.transient {
+				t.Errorf("[case: %s] expected to be transient: %t; got %t", tc.desc, tc.transient, transient)
+			}
+		})
+	}
+}
+
+func TestRetryCount(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		headers  amqp.Table
+		expected int64
+	}{
+		{
+			desc:     "NoHeaders",
+			headers:  nil,
+			expected: 0,
+		},
+		{
+			desc:     "Int32",
+			headers:  amqp.Table{headerRetryCount: int32(2)},
+			expected: 2,
+		},
+		{
+			desc:     "Int64",
+			headers:  amqp.Table{headerRetryCount: int64(3)},
+			expected: 3,
+		},
+		{
+			desc:     "UnexpectedType",
+			headers:  amqp.Table{headerRetryCount: "2"},
+			expected: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			count := retryCount(&amqp.Delivery{Headers: tc.headers})
+			if count != tc.expected {
+				t.Errorf("[case: %s] expected to get %d; got %d", tc.desc, tc.expected, count)
+			}
+		})
+	}
+}
+
+func TestFailedMessage(t *testing.T) {
+	cause := errors.New("connection reset by peer")
+	timestamp := time.Now().Truncate(time.Second)
+
+	testCases := []struct {
+		desc             string
+		delivery         *amqp.Delivery
+		count            int64
+		originalExchange string
+		originalKey      string
+	}{
+		{
+			desc: "FirstFailure",
+			delivery: &amqp.Delivery{
+				Headers:    amqp.Table{"x-custom": "value"},
+				Exchange:   "",
+				RoutingKey: "requests",
+			},
+			count:            1,
+			originalExchange: "",
+			originalKey:      "requests",
+		},
+		{
+			desc: "RetriedFailure",
+			delivery: &amqp.Delivery{
+				Headers: amqp.Table{
+					"x-custom":               "value",
+					headerRetryCount:         int64(1),
+					headerOriginalExchange:   "",
+					headerOriginalRoutingKey: "requests",
+				},
+				Exchange:   "retry",
+				RoutingKey: "requests",
+			},
+			count:            2,
+			originalExchange: "",
+			originalKey:      "requests",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tc.delivery.ContentType = "application/json"
+			tc.delivery.CorrelationId = "correlation"
+			tc.delivery.ReplyTo = "replies"
+			tc.delivery.MessageId = "message"
+			tc.delivery.Timestamp = timestamp
+			tc.delivery.Type = msgTypeList
+			tc.delivery.AppId = "client"
+			tc.delivery.Body = []byte(`{"type":"list"}`)
+
+			headersBefore := len(tc.delivery.Headers)
+
+			msg := failedMessage(tc.delivery, tc.count, cause)
+
+			if len(tc.delivery.Headers) != headersBefore {
+				t.Errorf("[case: %s] expected the headers of the delivery to be left untouched; got %v", tc.desc, tc.delivery.Headers)
+			}
+			if msg.Headers["x-custom"] != "value" {
+				t.Errorf("[case: %s] expected to keep the headers of the delivery; got %v", tc.desc, msg.Headers)
+			}
+			if msg.Headers[headerRetryCount] != tc.count {
+				t.Errorf("[case: %s] expected the retry count %d; got %v", tc.desc, tc.count, msg.Headers[headerRetryCount])
+			}
+			if msg.Headers[headerFailureReason] != cause.Error() {
+				t.Errorf("[case: %s] expected the failure reason %q; got %v", tc.desc, cause, msg.Headers[headerFailureReason])
+			}
+			if _, err := time.Parse(time.RFC3339, fmt.Sprint(msg.Headers[headerFailedAt])); err != nil {
+				t.Errorf("[case: %s] expected the failure time in RFC3339; got %v", tc.desc, msg.Headers[headerFailedAt])
+			}
+			if msg.Headers[headerOriginalExchange] != tc.originalExchange || msg.Headers[headerOriginalRoutingKey] != tc.originalKey {
+				t.Errorf("[case: %s] expected the original route %q/%q; got %v/%v", tc.desc, tc.originalExchange, tc.originalKey, msg.Headers[headerOriginalExchange], msg.Headers[headerOriginalRoutingKey])
+			}
+			if msg.DeliveryMode != amqp.Persistent {
+				t.Errorf("[case: %s] expected a persistent message; got delivery mode %d", tc.desc, msg.DeliveryMode)
+			}
+
+			expected := amqp.Publishing{
+				Headers:       msg.Headers,
+				ContentType:   tc.delivery.ContentType,
+				DeliveryMode:  amqp.Persistent,
+				CorrelationId: tc.delivery.CorrelationId,
+				ReplyTo:       tc.delivery.ReplyTo,
+				MessageId:     tc.delivery.MessageId,
+				Timestamp:     tc.delivery.Timestamp,
+				Type:          tc.delivery.Type,
+				AppId:         tc.delivery.AppId,
+				Body:          tc.delivery.Body,
+			}
+			if !reflect.DeepEqual(msg, expected) {
+				t.Errorf("[case: %s] expected to copy the delivery %+v; got %+v", tc.desc, expected, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package rmq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/streadway/amqp"
+	rmqconfig "golang-hexagon/internal/adapter/config/rmq"
+)
+
+type (
+	// topology is the set of exchanges, queues and bindings the handler relies on
+	topology struct {
+		Exchanges []exchangeSpec `json:"exchanges"`
+		Queues    []queueSpec    `json:"queues"`
+		Bindings  []bindingSpec  `json:"bindings"`
+	}
+
+	// exchangeSpec represents an exchange of the topology
+	exchangeSpec struct {
+		Name       string         `json:"name"`
+		Type       string         `json:"type"`
+		Durable    bool           `json:"durable"`
+		AutoDelete bool           `json:"auto_delete"`
+		Internal   bool           `json:"internal"`
+		Args       map[string]any `json:"args"`
+	}
+
+	// queueSpec represents a queue of the topology.
+	// The dead-letter exchange is a pointer since the default exchange has an empty name
+	queueSpec struct {
+		Name                 string         `json:"name"`
+		Durable              bool           `json:"durable"`
+		Quorum               bool           `json:"quorum"`
+		AutoDelete           bool           `json:"auto_delete"`
+		Exclusive            bool           `json:"exclusive"`
+		DeadLetterExchange   *string        `json:"dead_letter_exchange"`
+		DeadLetterRoutingKey string         `json:"dead_letter_routing_key"`
+		MessageTTL           string         `json:"message_ttl"`
+		Args                 map[string]any `json:"args"`
+	}
+
+	// bindingSpec represents a binding of a queue to an exchange of the topology
+	bindingSpec struct {
+		Queue      string         `json:"queue"`
+		Exchange   string         `json:"exchange"`
+		RoutingKey string         `json:"routing_key"`
+		Args       map[string]any `json:"args"`
+	}
+)
+
+// exchangeTypes are the exchange types supported by the broker without plugins
+var exchangeTypes = map[string]struct{}{
+	amqp.ExchangeDirect:  {},
+	amqp.ExchangeFanout:  {},
+	amqp.ExchangeTopic:   {},
+	amqp.ExchangeHeaders: {},
+}
+
+// loadTopology loads the topology either from the topology file or from the environment variables
+// and validates it against the exchanges and queues the handler uses
+func loadTopology(conf *rmqconfig.Config, retryDelay time.Duration) (*topology, error) {
+	t := defaultTopology(conf, retryDelay)
+
+	if conf.TopologyFile != "" {
+		content, err := os.ReadFile(conf.TopologyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		t = &topology{}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topology file: %w", err)
+		}
+	}
+
+	err := t.validate(conf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	return t, nil
+}
+
+// defaultTopology returns the topology described by the environment variables: a durable requests queue,
+// the responses exchange with its queue and the retry and dead-letter exchanges with their queues if configured.
+// The retry queue holds the messages for the retry delay and then dead-letters them back to the requests queue
+func defaultTopology(conf *rmqconfig.Config, retryDelay time.Duration) *topology {
+	t := &topology{
+		Queues: []queueSpec{
+			{Name: conf.InQueue, Durable: true},
+		},
+	}
+
+	addBoundQueue := func(exchange string, queue queueSpec, routingKey string) {
+		t.Exchanges = append(t.Exchanges, exchangeSpec{Name: exchange, Type: amqp.ExchangeDirect, Durable: true})
+		t.Queues = append(t.Queues, queue)
+		t.Bindings = append(t.Bindings, bindingSpec{Queue: queue.Name, Exchange: exchange, RoutingKey: routingKey})
+	}
+
+	// responses go to the default exchange if no exchange is configured
+	if conf.OutExchange != "" {
+		addBoundQueue(conf.OutExchange, queueSpec{Name: conf.OutRoutingKey, Durable: true}, conf.OutRoutingKey)
+	}
+
+	if conf.RetryExchange != "" {
+		defaultExchange := ""
+		addBoundQueue(conf.RetryExchange, queueSpec{
+			Name:                 conf.RetryQueue,
+			Durable:              true,
+			DeadLetterExchange:   &defaultExchange,
+			DeadLetterRoutingKey: conf.InQueue,
+			MessageTTL:           retryDelay.String(),
+		}, conf.InQueue)
+	}
+
+	if conf.DeadLetterExchange != "" {
+		addBoundQueue(conf.DeadLetterExchange, queueSpec{Name: conf.DeadLetterQueue, Durable: true}, conf.InQueue)
+	}
+
+	return t
+}
+
+// validate checks that the topology is consistent and declares the exchanges and queues the handler uses
+func (t *topology) validate(conf *rmqconfig.Config) error {
+	exchanges := make(map[string]struct{}, len(t.Exchanges))
+	for _, exchange := range t.Exchanges {
+		if exchange.Name == "" {
+			return errors.New("exchange without a name")
+		}
+		if _, ok := exchanges[exchange.Name]; ok {
+			return fmt.Errorf("exchange %q is declared twice", exchange.Name)
+		}
+		if _, ok := exchangeTypes[exchange.Type]; !ok {
+			return fmt.Errorf("exchange %q has unknown type %q", exchange.Name, exchange.Type)
+		}
+		exchanges[exchange.Name] = struct{}{}
+	}
+
+	queues := make(map[string]struct{}, len(t.Queues))
+	for _, queue := range t.Queues {
+		if queue.Name == "" {
+			return errors.New("queue without a name")
+		}
+		if _, ok := queues[queue.Name]; ok {
+			return fmt.Errorf("queue %q is declared twice", queue.Name)
+		}
+		if queue.Quorum && (!queue.Durable || queue.AutoDelete || queue.Exclusive) {
+			return fmt.Errorf("quorum queue %q must be durable and neither auto delete nor exclusive", queue.Name)
+		}
+		if queue.MessageTTL != "" {
+			if _, err := time.ParseDuration(queue.MessageTTL); err != nil {
+				return fmt.Errorf("queue %q has invalid message ttl: %w", queue.Name, err)
+			}
+		}
+		if dlx := queue.DeadLetterExchange; dlx != nil && *dlx != "" {
+			if _, ok := exchanges[*dlx]; !ok {
+				return fmt.Errorf("queue %q dead-letters to undeclared exchange %q", queue.Name, *dlx)
+			}
+		}
+		queues[queue.Name] = struct{}{}
+	}
+
+	for _, binding := range t.Bindings {
+		if _, ok := queues[binding.Queue]; !ok {
+			return fmt.Errorf("binding to undeclared queue %q", binding.Queue)
+		}
+		if _, ok := exchanges[binding.Exchange]; !ok {
+			return fmt.Errorf("binding of queue %q to undeclared exchange %q", binding.Queue, binding.Exchange)
+		}
+	}
+
+	if _, ok := queues[conf.InQueue]; !ok {
+		return fmt.Errorf("requests queue %q is not declared", conf.InQueue)
+	}
+
+	for _, exchange := range []string{conf.OutExchange, conf.RetryExchange, conf.DeadLetterExchange} {
+		if _, ok := exchanges[exchange]; exchange != "" && !ok {
+			return fmt.Errorf("exchange %q is not declared", exchange)
+		}
+	}
+
+	return nil
+}
+
+// declare declares the topology over the channel.
+// In passive mode nothing is created: every exchange and queue has to exist, and redeclaring them
+// with the same properties fails if they diverge. Bindings can not be verified over AMQP and are skipped
+func (t *topology) declare(ch *amqp.Channel, passive bool) error {
+	for _, exchange := range t.Exchanges {
+		if passive {
+			err := ch.ExchangeDeclarePassive(exchange.Name, exchange.Type, exchange.Durable, exchange.AutoDelete, exchange.Internal, false, nil)
+			if err != nil {
+				return fmt.Errorf("exchange %q: %w", exchange.Name, err)
+			}
+		}
+
+		err := ch.ExchangeDeclare(
+			exchange.Name,          // name
+			exchange.Type,          // kind
+			exchange.Durable,       // durable
+			exchange.AutoDelete,    // auto delete
+			exchange.Internal,      // internal
+			false,                  // no wait
+			toTable(exchange.Args), // args
+		)
+		if err != nil {
+			return fmt.Errorf("exchange %q: %w", exchange.Name, err)
+		}
+	}
+
+	for _, queue := range t.Queues {
+		if passive {
+			_, err := ch.QueueDeclarePassive(queue.Name, queue.Durable, queue.AutoDelete, queue.Exclusive, false, nil)
+			if err != nil {
+				return fmt.Errorf("queue %q: %w", queue.Name, err)
+			}
+		}
+
+		_, err := ch.QueueDeclare(
+			queue.Name,       // name
+			queue.Durable,    // durable
+			queue.AutoDelete, // delete when unused
+			queue.Exclusive,  // exclusive
+			false,            // no wait
+			queue.args(),     // args
+		)
+		if err != nil {
+			return fmt.Errorf("queue %q: %w", queue.Name, err)
+		}
+	}
+
+	if passive {
+		return nil
+	}
+
+	for _, binding := range t.Bindings {
+		err := ch.QueueBind(
+			binding.Queue,         // queue
+			binding.RoutingKey,    // routing key
+			binding.Exchange,      // exchange
+			false,                 // no wait
+			toTable(binding.Args), // args
+		)
+		if err != nil {
+			return fmt.Errorf("binding of queue %q to exchange %q: %w", binding.Queue, binding.Exchange, err)
+		}
+	}
+
+	return nil
+}
+
+// args returns the arguments of the queue with the flags of the spec applied
+func (q *queueSpec) args() amqp.Table {
+	args := toTable(q.Args)
+	if args == nil {
+		args = amqp.Table{}
+	}
+
+	if q.Quorum {
+		args["x-queue-type"] = "quorum"
+	}
+	if q.DeadLetterExchange != nil {
+		args["x-dead-letter-exchange"] = *q.DeadLetterExchange
+	}
+	if q.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-routing-key"] = q.DeadLetterRoutingKey
+	}
+	if q.MessageTTL != "" {
+		// the duration is validated when the topology is loaded
+		ttl, _ := time.ParseDuration(q.MessageTTL)
+		args["x-message-ttl"] = ttl.Milliseconds()
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// toTable converts the arguments decoded from JSON to an AMQP table with integer numbers where possible,
+// since the broker rejects floating point values for arguments like x-message-ttl
+func toTable(args map[string]any) amqp.Table {
+	if len(args) == 0 {
+		return nil
+	}
+
+	table := make(amqp.Table, len(args))
+	for key, value := range args {
+		if number, ok := value.(json.Number); ok {
+			if i, err := number.Int64(); err == nil {
+				value = i
+			} else if f, err := number.Float64(); err == nil {
+				value = f
+			}
+		}
+		table[key] = value
+	}
+
+	return table
+}
+
+// isTopologyError reports whether the broker refused the topology because an entity is missing
+// or diverges from its declaration, which reconnecting does not resolve
+func isTopologyError(err error) bool {
+	var amqpErr *amqp.Error
+	if !errors.As(err, &amqpErr) {
+		return false
+	}
+
+	return amqpErr.Code == amqp.NotFound || amqpErr.Code == amqp.PreconditionFailed
+}
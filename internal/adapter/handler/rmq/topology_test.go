@@ -0,0 +1,160 @@
+package rmq
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	rmqconfig "golang-hexagon/internal/adapter/config/rmq"
+)
+
+func TestTopology_Validate(t *testing.T) {
+	conf := &rmqconfig.Config{
+		InQueue:            "requests",
+		OutExchange:        "responses",
+		OutRoutingKey:      "responses",
+		RetryExchange:      "retry",
+		RetryQueue:         "retry",
+		DeadLetterExchange: "dead",
+		DeadLetterQueue:    "dead",
+	}
+	unknownExchange := "unknown"
+
+	testCases := []struct {
+		desc     string
+		topology func() *topology
+		err      string
+	}{
+		{
+			desc: "Success_Default",
+			topology: func() *topology {
+				return defaultTopology(conf, 5*time.Second)
+			},
+		},
+		{
+			desc: "Fail_ExchangeWithoutName",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Exchanges = append(t.Exchanges, exchangeSpec{Type: amqp.ExchangeDirect})
+				return t
+			},
+			err: "exchange without a name",
+		},
+		{
+			desc: "Fail_ExchangeDeclaredTwice",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Exchanges = append(t.Exchanges, t.Exchanges[0])
+				return t
+			},
+			err: "declared twice",
+		},
+		{
+			desc: "Fail_UnknownExchangeType",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Exchanges[0].Type = "x-delayed-message"
+				return t
+			},
+			err: "unknown type",
+		},
+		{
+			desc: "Fail_QueueWithoutName",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Queues = append(t.Queues, queueSpec{Durable: true})
+				return t
+			},
+			err: "queue without a name",
+		},
+		{
+			desc: "Fail_QueueDeclaredTwice",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Queues = append(t.Queues, t.Queues[0])
+				return t
+			},
+			err: "declared twice",
+		},
+		{
+			desc: "Fail_QuorumQueueNotDurable",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Queues[0].Quorum = true
+				t.Queues[0].Durable = false
+				return t
+			},
+			err: "must be durable",
+		},
+		{
+			desc: "Fail_InvalidMessageTTL",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Queues[0].MessageTTL = "5"
+				return t
+			},
+			err: "invalid message ttl",
+		},
+		{
+			desc: "Fail_UndeclaredDeadLetterExchange",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Queues[0].DeadLetterExchange = &unknownExchange
+				return t
+			},
+			err: "dead-letters to undeclared exchange",
+		},
+		{
+			desc: "Fail_BindingToUndeclaredQueue",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Bindings = append(t.Bindings, bindingSpec{Queue: "unknown", Exchange: conf.OutExchange})
+				return t
+			},
+			err: "binding to undeclared queue",
+		},
+		{
+			desc: "Fail_BindingToUndeclaredExchange",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Bindings = append(t.Bindings, bindingSpec{Queue: conf.InQueue, Exchange: unknownExchange})
+				return t
+			},
+			err: "to undeclared exchange",
+		},
+		{
+			desc: "Fail_RequestsQueueNotDeclared",
+			topology: func() *topology {
+				t := defaultTopology(conf, 5*time.Second)
+				t.Queues = t.Queues[1:]
+				return t
+			},
+			err: "requests queue",
+		},
+		{
+			desc: "Fail_UsedExchangeNotDeclared",
+			topology: func() *topology {
+				return &topology{
+					Queues: []queueSpec{{Name: conf.InQueue, Durable: true}},
+				}
+			},
+			err: "is not declared",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.topology().validate(conf)
+			if tc.err == "" && err != nil {
+				t.Errorf("[case: %s] expected the topology to be valid; got %q", tc.desc, err)
+			}
+			if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+				t.Errorf("[case: %s] expected to get an error containing %q; got %v", tc.desc, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"golang-hexagon/internal/core/port"
+	"log/slog"
+	"time"
+)
+
+// RunOutboxRelay relays the pending events of the outbox every relay interval, draining full batches at once,
+// and deletes the delivered events every cleanup interval until the context is done
+func RunOutboxRelay(ctx context.Context, outbox port.OutboxService, batchSize uint64, relayInterval, cleanupInterval time.Duration) {
+	relayTicker := time.NewTicker(relayInterval)
+	defer relayTicker.Stop()
+
+	cleanupTicker := time.NewTicker(cleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-relayTicker.C:
+			for {
+				sent, err := outbox.RelayPending(ctx)
+				if err != nil {
+					slog.Error("Error relaying outbox events", "error", err)
+					break
+				}
+				if sent == 0 || uint64(sent) < batchSize {
+					break
+				}
+			}
+		case <-cleanupTicker.C:
+			deleted, err := outbox.Cleanup(ctx)
+			if err != nil {
+				slog.Error("Error cleaning up outbox events", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				slog.Info("Cleaned up delivered outbox events", "deleted", deleted)
+			}
+		}
+	}
+}
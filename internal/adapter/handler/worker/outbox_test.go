@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"golang-hexagon/internal/core/port/mock"
+)
+
+func TestRunOutboxRelay(t *testing.T) {
+	const batchSize = 100
+
+	testCases := []struct {
+		desc  string
+		mocks func(outbox *mock.MockOutboxService, cancel context.CancelFunc)
+	}{
+		{
+			desc: "DrainFullBatches",
+			mocks: func(outbox *mock.MockOutboxService, cancel context.CancelFunc) {
+				gomock.InOrder(
+					outbox.EXPECT().RelayPending(gomock.Any()).Return(batchSize, nil),
+					outbox.EXPECT().RelayPending(gomock.Any()).Return(batchSize, nil),
+					outbox.EXPECT().RelayPending(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+						cancel()
+						return 3, nil
+					}),
+					relayedAfterCancel(outbox),
+				)
+			},
+		},
+		{
+			desc: "StopOnEmptyBatch",
+			mocks: func(outbox *mock.MockOutboxService, cancel context.CancelFunc) {
+				gomock.InOrder(
+					outbox.EXPECT().RelayPending(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+						cancel()
+						return 0, nil
+					}),
+					relayedAfterCancel(outbox),
+				)
+			},
+		},
+		{
+			desc: "StopOnError",
+			mocks: func(outbox *mock.MockOutboxService, cancel context.CancelFunc) {
+				gomock.InOrder(
+					outbox.EXPECT().RelayPending(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+						cancel()
+						return 0, errors.New("connection refused")
+					}),
+					relayedAfterCancel(outbox),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			outbox := mock.NewMockOutboxService(ctrl)
+			tc.mocks(outbox, cancel)
+
+			done := make(chan struct{})
+			go func() {
+				RunOutboxRelay(ctx, outbox, batchSize, time.Millisecond, time.Hour)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Errorf("[case: %s] expected the relay to stop when the context is done", tc.desc)
+			}
+		})
+	}
+}
+
+// relayedAfterCancel allows the relay tick that may race with the cancellation of the context
+func relayedAfterCancel(outbox *mock.MockOutboxService) *gomock.Call {
+	return outbox.EXPECT().RelayPending(gomock.Any()).Return(0, nil).AnyTimes()
+}
@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"golang-hexagon/internal/core/port"
+	"log/slog"
+	"time"
+)
+
+// RunUserPurge permanently deletes the users deleted longer ago than the retention every purge interval
+// until the context is done
+func RunUserPurge(ctx context.Context, users port.UserService, purgeInterval time.Duration) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := users.PurgeDeletedUsers(ctx)
+			if err != nil {
+				slog.Error("Error purging deleted users", "error", err)
+				continue
+			}
+			if purged > 0 {
+				slog.Info("Purged deleted users", "purged", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// File implements port.Notifier interface
+// and writes notifications to a file or to the log for local development
+type File struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates a new file notifier instance.
+// Notifications are logged if the file path is empty
+func New(config *config.Notifier) port.Notifier {
+	return &File{
+		path: config.File,
+	}
+}
+
+// Notify appends the notification to the file or logs it
+func (f *File) Notify(ctx context.Context, notification *domain.Notification) error {
+	if f.path == "" {
+		slog.Info("Notification",
+			"to", notification.To,
+			"subject", notification.Subject,
+			"body", notification.Body,
+		)
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n---\n",
+		time.Now().Format(time.RFC3339),
+		notification.To,
+		notification.Subject,
+		notification.Body,
+	)
+
+	return err
+}
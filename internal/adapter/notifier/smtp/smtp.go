@@ -0,0 +1,52 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP implements port.Notifier interface
+// and sends notifications as emails through the SMTP server
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// New creates a new SMTP notifier instance
+func New(config *config.Notifier) port.Notifier {
+	var auth smtp.Auth
+	if config.SMTPUser != "" {
+		auth = smtp.PlainAuth("", config.SMTPUser, config.SMTPPassword, config.SMTPHost)
+	}
+
+	return &SMTP{
+		net.JoinHostPort(config.SMTPHost, config.SMTPPort),
+		auth,
+		config.From,
+	}
+}
+
+// Notify sends the notification as a plain text email
+func (s *SMTP) Notify(ctx context.Context, notification *domain.Notification) error {
+	var msg strings.Builder
+
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", notification.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(notification.Body, "\n", "\r\n"))
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{notification.To}, []byte(msg.String()))
+}
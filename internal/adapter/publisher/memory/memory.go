@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+	"sync"
+)
+
+// maxEvents is the number of the latest events kept in memory
+const maxEvents = 1000
+
+// Publisher implements port.EventPublisher interface
+// and keeps the latest published events in memory for tests.
+// It is not used by the applications, since the events it keeps are lost when the process stops
+type Publisher struct {
+	mu     sync.Mutex
+	events []*domain.Event
+}
+
+// New creates a new in-memory event publisher instance
+func New() *Publisher {
+	return &Publisher{}
+}
+
+// Publish keeps the event, the oldest event is dropped when the limit is reached
+func (p *Publisher) Publish(ctx context.Context, event *domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.events) == maxEvents {
+		p.events = p.events[1:]
+	}
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+// Events returns the published events in the order they were published
+func (p *Publisher) Events() []*domain.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]*domain.Event, len(p.events))
+	copy(events, p.events)
+
+	return events
+}
+
+// Reset drops the published events
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = nil
+}
@@ -0,0 +1,129 @@
+package rmq
+
+import (
+	"context"
+	"encoding/json"
+	"golang-hexagon/internal/adapter/config"
+	"golang-hexagon/internal/core/domain"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const contentType = "application/json"
+
+// eventMessage represents the event published to the exchange
+type eventMessage struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	UserID     uint64         `json:"user_id"`
+	Data       map[string]any `json:"data,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+// Publisher implements port.EventPublisher interface
+// and publishes events to a RabbitMQ topic exchange with the event type as routing key,
+// so consumers can bind to "user.*" or to single event types
+type Publisher struct {
+	url      string
+	exchange string
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+}
+
+// New creates a new RabbitMQ event publisher instance and declares its exchange
+func New(config *config.Events) (*Publisher, error) {
+	p := &Publisher{
+		url:      config.URL,
+		exchange: config.Exchange,
+	}
+
+	err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Publish publishes the event as a persistent message.
+// The connection is re-established if the broker closed it since the last event
+func (p *Publisher) Publish(ctx context.Context, event *domain.Event) error {
+	body, err := json.Marshal(&eventMessage{
+		ID:         event.ID.String(),
+		Type:       string(event.Type),
+		UserID:     event.UserID,
+		Data:       event.Data,
+		OccurredAt: event.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn.IsClosed() {
+		err = p.connect()
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.ch.Publish(
+		p.exchange,         // exchange
+		string(event.Type), // routing key
+		false,              // mandatory
+		false,              // immediate
+		amqp.Publishing{
+			ContentType:  contentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    event.ID.String(),
+			Type:         string(event.Type),
+			Timestamp:    event.OccurredAt,
+			Body:         body,
+		},
+	)
+}
+
+// Close closes the connection to the broker
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.conn.Close()
+}
+
+// connect opens the connection and the channel and declares the topic exchange
+func (p *Publisher) connect() error {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	err = ch.ExchangeDeclare(
+		p.exchange,         // name
+		amqp.ExchangeTopic, // kind
+		true,               // durable
+		false,              // auto delete
+		false,              // internal
+		false,              // no wait
+		nil,                // args
+	)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	p.conn, p.ch = conn, ch
+
+	return nil
+}
@@ -0,0 +1,14 @@
+CREATE TABLE "refresh_tokens" (
+     "id" uuid PRIMARY KEY,
+     "family_id" uuid NOT NULL,
+     "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+     "token_hash" varchar NOT NULL,
+     "expires_at" timestamptz NOT NULL,
+     "rotated_at" timestamptz,
+     "revoked_at" timestamptz,
+     "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+
+CREATE INDEX "refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
@@ -0,0 +1,8 @@
+DO $$
+BEGIN
+    CREATE TYPE "users_role_enum" AS ENUM ('admin', 'basic');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" "users_role_enum" NOT NULL DEFAULT 'basic';
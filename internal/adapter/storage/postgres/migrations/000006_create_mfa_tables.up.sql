@@ -0,0 +1,16 @@
+CREATE TABLE "user_totp" (
+     "user_id" bigint PRIMARY KEY REFERENCES "users" ("id") ON DELETE CASCADE,
+     "secret" varchar NOT NULL,
+     "confirmed_at" timestamptz,
+     "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "mfa_recovery_codes" (
+     "id" uuid PRIMARY KEY,
+     "user_id" bigint NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+     "code_hash" varchar NOT NULL,
+     "used_at" timestamptz,
+     "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "mfa_recovery_codes_user_id" ON "mfa_recovery_codes" ("user_id");
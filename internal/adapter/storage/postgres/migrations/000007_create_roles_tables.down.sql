@@ -0,0 +1,22 @@
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_role_fkey";
+
+DO $$
+BEGIN
+    CREATE TYPE "users_role_enum" AS ENUM ('admin', 'basic');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+UPDATE "users" SET "role" = 'basic' WHERE "role" NOT IN ('admin', 'basic');
+
+ALTER TABLE "users" ALTER COLUMN "role" DROP DEFAULT;
+
+ALTER TABLE "users" ALTER COLUMN "role" TYPE "users_role_enum" USING "role"::"users_role_enum";
+
+ALTER TABLE "users" ALTER COLUMN "role" SET DEFAULT 'basic';
+
+DROP TABLE IF EXISTS "role_permissions";
+
+DROP TABLE IF EXISTS "permissions";
+
+DROP TABLE IF EXISTS "roles";
\ No newline at end of file
@@ -0,0 +1,59 @@
+CREATE TABLE "roles" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "name" varchar NOT NULL,
+     "description" varchar NOT NULL DEFAULT '',
+     "created_at" timestamptz NOT NULL DEFAULT (now()),
+     "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "roles_name" ON "roles" ("name");
+
+CREATE TABLE "permissions" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "resource" varchar NOT NULL,
+     "action" varchar NOT NULL,
+     "description" varchar NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX "permissions_resource_action" ON "permissions" ("resource", "action");
+
+CREATE TABLE "role_permissions" (
+     "role_id" bigint NOT NULL REFERENCES "roles" ("id") ON DELETE CASCADE,
+     "permission_id" bigint NOT NULL REFERENCES "permissions" ("id") ON DELETE CASCADE,
+     PRIMARY KEY ("role_id", "permission_id")
+);
+
+INSERT INTO "roles" ("name", "description") VALUES
+     ('admin', 'Full access to users, sessions and roles'),
+     ('basic', 'Default role of registered users');
+
+INSERT INTO "permissions" ("resource", "action", "description") VALUES
+     ('users', 'read', 'List and view users'),
+     ('users', 'update', 'Update any user'),
+     ('users', 'delete', 'Delete any user'),
+     ('users', 'unlock', 'Unlock accounts locked after failed logins'),
+     ('sessions', 'revoke', 'Revoke all sessions of any user'),
+     ('roles', 'read', 'List and view roles and permissions'),
+     ('roles', 'create', 'Create roles'),
+     ('roles', 'update', 'Change the description and permissions of roles'),
+     ('roles', 'delete', 'Delete roles');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id"
+FROM "roles" CROSS JOIN "permissions"
+WHERE "roles"."name" = 'admin';
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id"
+FROM "roles" JOIN "permissions" ON "permissions"."resource" = 'users' AND "permissions"."action" = 'read'
+WHERE "roles"."name" = 'basic';
+
+ALTER TABLE "users" ALTER COLUMN "role" DROP DEFAULT;
+
+ALTER TABLE "users" ALTER COLUMN "role" TYPE varchar USING "role"::varchar;
+
+ALTER TABLE "users" ALTER COLUMN "role" SET DEFAULT 'basic';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_fkey" FOREIGN KEY ("role") REFERENCES "roles" ("name");
+
+DROP TYPE IF EXISTS "users_role_enum";
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM "permissions" WHERE "resource" = 'users' AND "action" = 'read_details';
\ No newline at end of file
@@ -0,0 +1,7 @@
+INSERT INTO "permissions" ("resource", "action", "description") VALUES
+     ('users', 'read_details', 'View the email and account details of any user');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id"
+FROM "roles" JOIN "permissions" ON "permissions"."resource" = 'users' AND "permissions"."action" = 'read_details'
+WHERE "roles"."name" = 'admin';
\ No newline at end of file
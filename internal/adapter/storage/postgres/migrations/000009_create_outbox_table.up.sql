@@ -0,0 +1,19 @@
+CREATE TABLE "outbox" (
+     "id" BIGSERIAL PRIMARY KEY,
+     "event_id" uuid NOT NULL,
+     "event_type" varchar NOT NULL,
+     "user_id" bigint NOT NULL,
+     "data" jsonb,
+     "occurred_at" timestamptz NOT NULL,
+     "attempts" integer NOT NULL DEFAULT 0,
+     "last_error" varchar NOT NULL DEFAULT '',
+     "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+     "sent_at" timestamptz,
+     "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX "outbox_event_id" ON "outbox" ("event_id");
+
+CREATE INDEX "outbox_pending" ON "outbox" ("next_attempt_at", "id") WHERE "sent_at" IS NULL;
+
+CREATE INDEX "outbox_sent_at" ON "outbox" ("sent_at") WHERE "sent_at" IS NOT NULL;
@@ -0,0 +1,5 @@
+DELETE FROM "permissions" WHERE "resource" = 'users' AND "action" IN ('restore', 'purge');
+
+DELETE FROM "users" WHERE "deleted_at" IS NOT NULL;
+
+ALTER TABLE "users" DROP COLUMN "deleted_at";
\ No newline at end of file
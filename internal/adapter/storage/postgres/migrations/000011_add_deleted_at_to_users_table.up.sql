@@ -0,0 +1,12 @@
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz;
+
+CREATE INDEX "users_deleted_at" ON "users" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+INSERT INTO "permissions" ("resource", "action", "description") VALUES
+     ('users', 'restore', 'List and restore deleted users'),
+     ('users', 'purge', 'Permanently delete deleted users');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT "roles"."id", "permissions"."id"
+FROM "roles" JOIN "permissions" ON "permissions"."resource" = 'users' AND "permissions"."action" IN ('restore', 'purge')
+WHERE "roles"."name" = 'admin';
\ No newline at end of file
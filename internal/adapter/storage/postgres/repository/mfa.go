@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"golang-hexagon/internal/adapter/storage/postgres"
+	"golang-hexagon/internal/core/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// MFARepository implements port.MFARepository interface
+// and provides access to the postgres database
+type MFARepository struct {
+	db *postgres.DB
+}
+
+// NewMFARepository creates a new multi-factor authentication repository instance
+func NewMFARepository(db *postgres.DB) *MFARepository {
+	return &MFARepository{
+		db,
+	}
+}
+
+// UpsertTOTP creates a pending TOTP authenticator or replaces the existing one in the database
+func (r *MFARepository) UpsertTOTP(ctx context.Context, totp *domain.TOTP) (*domain.TOTP, error) {
+	query := r.db.QueryBuilder.Insert("user_totp").
+		Columns("user_id", "secret").
+		Values(totp.UserID, totp.Secret).
+		Suffix(`ON CONFLICT ("user_id") DO UPDATE SET "secret" = EXCLUDED."secret", "confirmed_at" = NULL, "created_at" = now() RETURNING *`)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return totp, nil
+}
+
+// GetTOTPByUserID gets the TOTP authenticator of the user from the database
+func (r *MFARepository) GetTOTPByUserID(ctx context.Context, userID uint64) (*domain.TOTP, error) {
+	var totp domain.TOTP
+
+	query := r.db.QueryBuilder.Select("*").
+		From("user_totp").
+		Where(sq.Eq{"user_id": userID}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+// ConfirmTOTP marks the pending TOTP authenticator of the user as confirmed in the database.
+// It returns domain.ErrDataNotFound if there is no pending authenticator
+func (r *MFARepository) ConfirmTOTP(ctx context.Context, userID uint64) error {
+	query := r.db.QueryBuilder.Update("user_totp").
+		Set("confirmed_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":      userID,
+			"confirmed_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// DeleteTOTP deletes the TOTP authenticator and the recovery codes of the user from the database
+func (r *MFARepository) DeleteTOTP(ctx context.Context, userID uint64) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for _, table := range []string{"mfa_recovery_codes", "user_totp"} {
+			sql, args, err := r.db.QueryBuilder.Delete(table).
+				Where(sq.Eq{"user_id": userID}).
+				ToSql()
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx, sql, args...)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReplaceRecoveryCodes deletes the recovery codes of the user and inserts the new ones in the database
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uint64, hashes []string) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		sql, args, err := r.db.QueryBuilder.Delete("mfa_recovery_codes").
+			Where(sq.Eq{"user_id": userID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		if len(hashes) == 0 {
+			return nil
+		}
+
+		query := r.db.QueryBuilder.Insert("mfa_recovery_codes").
+			Columns("id", "user_id", "code_hash")
+
+		for _, hash := range hashes {
+			id, err := uuid.NewRandom()
+			if err != nil {
+				return err
+			}
+
+			query = query.Values(id, userID, hash)
+		}
+
+		sql, args, err = query.ToSql()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, sql, args...)
+		return err
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used in the database.
+// It returns domain.ErrDataNotFound if there is no such unused code
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID uint64, hash string) error {
+	query := r.db.QueryBuilder.Update("mfa_recovery_codes").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":   userID,
+			"code_hash": hash,
+			"used_at":   nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
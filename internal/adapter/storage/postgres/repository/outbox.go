@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"golang-hexagon/internal/adapter/storage/postgres"
+	"golang-hexagon/internal/core/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// outboxColumns lists the outbox table columns in the order they are scanned
+const outboxColumns = "id, event_id, event_type, user_id, data, occurred_at, attempts, last_error, next_attempt_at, sent_at, created_at"
+
+// OutboxRepository implements port.OutboxRepository and port.EventPublisher interfaces
+// and provides access to the postgres database.
+// Events are published by inserting them into the outbox, so they are stored or discarded with the change they announce
+type OutboxRepository struct {
+	db *postgres.DB
+}
+
+// NewOutboxRepository creates a new outbox repository instance
+func NewOutboxRepository(db *postgres.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db,
+	}
+}
+
+// Publish inserts the event into the outbox in the database
+func (r *OutboxRepository) Publish(ctx context.Context, event *domain.Event) error {
+	query := r.db.QueryBuilder.Insert("outbox").
+		Columns("event_id", "event_type", "user_id", "data", "occurred_at").
+		Values(event.ID, string(event.Type), event.UserID, event.Data, event.OccurredAt)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimPending selects the pending entries that are due in the order they were stored from the database.
+// The entries stay locked until the ambient transaction ends, and the entries locked by other relays are skipped
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit uint64) ([]*domain.OutboxEntry, error) {
+	query := r.db.QueryBuilder.Select(outboxColumns).
+		From("outbox").
+		Where(sq.Eq{"sent_at": nil}).
+		Where(sq.Expr("next_attempt_at <= now()")).
+		OrderBy("id").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.OutboxEntry
+
+	for rows.Next() {
+		entry := domain.OutboxEntry{Event: &domain.Event{}}
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Event.ID,
+			&entry.Event.Type,
+			&entry.Event.UserID,
+			&entry.Event.Data,
+			&entry.Event.OccurredAt,
+			&entry.Attempts,
+			&entry.LastError,
+			&entry.NextAttemptAt,
+			&entry.SentAt,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// MarkSent marks an entry as relayed in the database
+func (r *OutboxRepository) MarkSent(ctx context.Context, id uint64) error {
+	query := r.db.QueryBuilder.Update("outbox").
+		Set("sent_at", sq.Expr("now()")).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed attempt of an entry in the database
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error {
+	query := r.db.QueryBuilder.Update("outbox").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("next_attempt_at", nextAttemptAt).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSent deletes the entries relayed before the given time from the database
+func (r *OutboxRepository) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	query := r.db.QueryBuilder.Delete("outbox").
+		Where(sq.NotEq{"sent_at": nil}).
+		Where(sq.Lt{"sent_at": before})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"golang-hexagon/internal/adapter/storage/postgres"
+	"golang-hexagon/internal/core/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PasswordResetRepository implements port.PasswordResetRepository interface
+// and provides access to the postgres database
+type PasswordResetRepository struct {
+	db *postgres.DB
+}
+
+// NewPasswordResetRepository creates a new password reset repository instance
+func NewPasswordResetRepository(db *postgres.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db,
+	}
+}
+
+// CreatePasswordResetToken creates a new password reset token in the database
+func (r *PasswordResetRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+	query := r.db.QueryBuilder.Insert("password_reset_tokens").
+		Columns("id", "user_id", "token_hash", "expires_at").
+		Values(token.ID, token.UserID, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING *")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GetPasswordResetTokenByHash gets a password reset token by the hash of its value from the database
+func (r *PasswordResetRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+
+	query := r.db.QueryBuilder.Select("*").
+		From("password_reset_tokens").
+		Where(sq.Eq{"token_hash": hash}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// UsePasswordResetToken marks an unused password reset token as used in the database.
+// It returns domain.ErrDataNotFound if the token has already been used
+func (r *PasswordResetRepository) UsePasswordResetToken(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Update("password_reset_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"id":      id,
+			"used_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// InvalidateUserPasswordResetTokens marks all unused password reset tokens of the user as used in the database
+func (r *PasswordResetRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userID uint64) error {
+	query := r.db.QueryBuilder.Update("password_reset_tokens").
+		Set("used_at", time.Now()).
+		Where(sq.Eq{
+			"user_id": userID,
+			"used_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
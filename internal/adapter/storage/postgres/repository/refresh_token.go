@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"golang-hexagon/internal/adapter/storage/postgres"
+	"golang-hexagon/internal/core/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// refreshTokenColumns lists the refresh_tokens table columns in the order they are scanned
+const refreshTokenColumns = "id, family_id, user_id, token_hash, expires_at, rotated_at, revoked_at, created_at"
+
+// RefreshTokenRepository implements port.RefreshTokenRepository interface
+// and provides access to the postgres database.
+// Queries take part in the ambient transaction of the context if there is one
+type RefreshTokenRepository struct {
+	db *postgres.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository instance
+func NewRefreshTokenRepository(db *postgres.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db,
+	}
+}
+
+// CreateRefreshToken creates a new refresh token in the database
+func (r *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	query := r.db.QueryBuilder.Insert("refresh_tokens").
+		Columns("id", "family_id", "user_id", "token_hash", "expires_at").
+		Values(token.ID, token.FamilyID, token.UserID, token.TokenHash, token.ExpiresAt).
+		Suffix("RETURNING " + refreshTokenColumns)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.FamilyID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RotatedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+			return nil, domain.ErrConflictingData
+		}
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// GetRefreshTokenByHash gets a refresh token by the hash of its value from the database
+func (r *RefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+
+	query := r.db.QueryBuilder.Select(refreshTokenColumns).
+		From("refresh_tokens").
+		Where(sq.Eq{"token_hash": hash}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(
+		&token.ID,
+		&token.FamilyID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.RotatedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken marks an active refresh token as rotated in the database.
+// It returns domain.ErrDataNotFound if the token has already been rotated or revoked
+func (r *RefreshTokenRepository) RotateRefreshToken(ctx context.Context, id uuid.UUID) error {
+	query := r.db.QueryBuilder.Update("refresh_tokens").
+		Set("rotated_at", time.Now()).
+		Where(sq.Eq{
+			"id":         id,
+			"rotated_at": nil,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenFamily revokes all refresh tokens of the family in the database
+func (r *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := r.db.QueryBuilder.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{
+			"family_id":  familyID,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes all refresh tokens of the user in the database
+func (r *RefreshTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uint64) error {
+	query := r.db.QueryBuilder.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":    userID,
+			"revoked_at": nil,
+		})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeOtherRefreshTokens revokes all active refresh tokens of the user except those of the family
+func (r *RefreshTokenRepository) RevokeOtherRefreshTokens(ctx context.Context, userID uint64, familyID uuid.UUID) error {
+	query := r.db.QueryBuilder.Update("refresh_tokens").
+		Set("revoked_at", time.Now()).
+		Where(sq.Eq{
+			"user_id":    userID,
+			"revoked_at": nil,
+		}).
+		Where(sq.NotEq{"family_id": familyID})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"context"
+	"golang-hexagon/internal/adapter/storage/postgres"
+	"golang-hexagon/internal/core/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// roleColumns lists the roles table columns in the order they are scanned
+const roleColumns = "id, name, description, created_at, updated_at"
+
+// permissionColumns lists the permissions table columns in the order they are scanned
+const permissionColumns = "permissions.id, permissions.resource, permissions.action, permissions.description"
+
+// RoleRepository implements port.RoleRepository interface
+// and provides access to the postgres database
+type RoleRepository struct {
+	db *postgres.DB
+}
+
+// NewRoleRepository creates a new role repository instance
+func NewRoleRepository(db *postgres.DB) *RoleRepository {
+	return &RoleRepository{
+		db,
+	}
+}
+
+// CreateRole creates a new role with its permissions in the database
+func (r *RoleRepository) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		sql, args, err := r.db.QueryBuilder.Insert("roles").
+			Columns("name", "description").
+			Values(role.Name, role.Description).
+			Suffix("RETURNING " + roleColumns).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx, sql, args...).Scan(
+			&role.ID,
+			&role.Name,
+			&role.Description,
+			&role.CreatedAt,
+			&role.UpdatedAt,
+		)
+		if err != nil {
+			if errCode := r.db.ErrorCode(err); errCode == "23505" {
+				return domain.ErrConflictingData
+			}
+			return err
+		}
+
+		role.Permissions, err = r.replacePermissions(ctx, tx, role.ID, role.Permissions)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// GetRoleByID gets a role with its permissions by ID from the database
+func (r *RoleRepository) GetRoleByID(ctx context.Context, id uint64) (*domain.Role, error) {
+	return r.getRole(ctx, sq.Eq{"id": id})
+}
+
+// GetRoleByName gets a role with its permissions by name from the database
+func (r *RoleRepository) GetRoleByName(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	return r.getRole(ctx, sq.Eq{"name": name})
+}
+
+// ListRoles lists roles with their permissions from the database
+func (r *RoleRepository) ListRoles(ctx context.Context, skip, limit uint64) ([]*domain.Role, error) {
+	var roles []*domain.Role
+
+	query := r.db.QueryBuilder.Select(roleColumns).
+		From("roles").
+		OrderBy("id").
+		Limit(limit).
+		Offset((skip - 1) * limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role domain.Role
+
+		err := rows.Scan(
+			&role.ID,
+			&role.Name,
+			&role.Description,
+			&role.CreatedAt,
+			&role.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, &role)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.loadPermissions(ctx, roles...)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// UpdateRole updates the description of a role and replaces its permissions if they are not nil in the database
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		sql, args, err := r.db.QueryBuilder.Update("roles").
+			Set("description", sq.Expr("COALESCE(?, description)", nullString(role.Description))).
+			Set("updated_at", time.Now()).
+			Where(sq.Eq{"id": role.ID}).
+			Suffix("RETURNING " + roleColumns).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx, sql, args...).Scan(
+			&role.ID,
+			&role.Name,
+			&role.Description,
+			&role.CreatedAt,
+			&role.UpdatedAt,
+		)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return domain.ErrDataNotFound
+			}
+			return err
+		}
+
+		if role.Permissions == nil {
+			return nil
+		}
+
+		role.Permissions, err = r.replacePermissions(ctx, tx, role.ID, role.Permissions)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if role.Permissions == nil {
+		err = r.loadPermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return role, nil
+}
+
+// DeleteRole deletes a role by ID from the database
+func (r *RoleRepository) DeleteRole(ctx context.Context, id uint64) error {
+	query := r.db.QueryBuilder.Delete("roles").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		if errCode := r.db.ErrorCode(err); errCode == "23503" {
+			return domain.ErrRoleInUse
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListPermissions lists all permissions from the database
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+
+	query := r.db.QueryBuilder.Select(permissionColumns).
+		From("permissions").
+		OrderBy("resource", "action")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var permission domain.Permission
+
+		err := rows.Scan(
+			&permission.ID,
+			&permission.Resource,
+			&permission.Action,
+			&permission.Description,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+// getRole gets a role matching the condition with its permissions from the database
+func (r *RoleRepository) getRole(ctx context.Context, where sq.Eq) (*domain.Role, error) {
+	var role domain.Role
+
+	query := r.db.QueryBuilder.Select(roleColumns).
+		From("roles").
+		Where(where).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(
+		&role.ID,
+		&role.Name,
+		&role.Description,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	err = r.loadPermissions(ctx, &role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// loadPermissions sets the permissions of the roles from the database
+func (r *RoleRepository) loadPermissions(ctx context.Context, roles ...*domain.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+
+	rolesByID := make(map[uint64]*domain.Role, len(roles))
+	roleIDs := make([]uint64, 0, len(roles))
+	for _, role := range roles {
+		role.Permissions = []domain.Permission{}
+		rolesByID[role.ID] = role
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	query := r.db.QueryBuilder.Select("role_permissions.role_id", permissionColumns).
+		From("role_permissions").
+		Join("permissions ON permissions.id = role_permissions.permission_id").
+		Where(sq.Eq{"role_permissions.role_id": roleIDs}).
+		OrderBy("permissions.resource", "permissions.action")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			roleID     uint64
+			permission domain.Permission
+		)
+
+		err := rows.Scan(
+			&roleID,
+			&permission.ID,
+			&permission.Resource,
+			&permission.Action,
+			&permission.Description,
+		)
+		if err != nil {
+			return err
+		}
+
+		role := rolesByID[roleID]
+		role.Permissions = append(role.Permissions, permission)
+	}
+
+	return rows.Err()
+}
+
+// replacePermissions replaces the permissions of the role within the transaction
+// and returns them as stored in the database.
+// It returns domain.ErrUnknownPermission if any of the permissions does not exist
+func (r *RoleRepository) replacePermissions(ctx context.Context, tx pgx.Tx, roleID uint64, permissions []domain.Permission) ([]domain.Permission, error) {
+	sql, args, err := r.db.QueryBuilder.Delete("role_permissions").
+		Where(sq.Eq{"role_id": roleID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := []domain.Permission{}
+	if len(permissions) == 0 {
+		return stored, nil
+	}
+
+	requested := make(map[string]struct{}, len(permissions))
+	conditions := make(sq.Or, 0, len(permissions))
+	for _, permission := range permissions {
+		requested[permission.String()] = struct{}{}
+		conditions = append(conditions, sq.Eq{
+			"resource": permission.Resource,
+			"action":   permission.Action,
+		})
+	}
+
+	sql, args, err = r.db.QueryBuilder.Select(permissionColumns).
+		From("permissions").
+		Where(conditions).
+		OrderBy("resource", "action").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var permission domain.Permission
+
+		err := rows.Scan(
+			&permission.ID,
+			&permission.Resource,
+			&permission.Action,
+			&permission.Description,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		stored = append(stored, permission)
+	}
+	rows.Close()
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stored) != len(requested) {
+		return nil, domain.ErrUnknownPermission
+	}
+
+	query := r.db.QueryBuilder.Insert("role_permissions").
+		Columns("role_id", "permission_id")
+
+	for _, permission := range stored {
+		query = query.Values(roleID, permission.ID)
+	}
+
+	sql, args, err = query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
@@ -11,8 +11,13 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// userColumns lists the users table columns in the order they are scanned
+const userColumns = "id, name, email, password, role, verified_at, created_at, updated_at, version, deleted_at"
+
 // UserRepository implements port.UserRepository interface
-// and provides access to the postgres database
+// and provides access to the postgres database.
+// Queries take part in the ambient transaction of the context if there is one.
+// Deleted users are only marked as deleted, and all queries but the ones for deleted users exclude them
 type UserRepository struct {
 	db *postgres.DB
 }
@@ -29,21 +34,24 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*do
 	query := r.db.QueryBuilder.Insert("users").
 		Columns("name", "email", "password").
 		Values(user.Name, user.Email, user.Password).
-		Suffix("RETURNING *")
+		Suffix("RETURNING " + userColumns)
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
+	err = r.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
 		&user.Password,
 		&user.Role,
+		&user.VerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Version,
+		&user.DeletedAt,
 	)
 	if err != nil {
 		if errCode := r.db.ErrorCode(err); errCode == "23505" {
@@ -57,26 +65,45 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) (*do
 
 // GetUserByID gets a user by ID from the database
 func (r *UserRepository) GetUserByID(ctx context.Context, id uint64) (*domain.User, error) {
-	var user domain.User
-
-	query := r.db.QueryBuilder.Select("*").
+	query := r.db.QueryBuilder.Select(userColumns).
 		From("users").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		Limit(1)
 
+	return r.getUser(ctx, query)
+}
+
+// GetUserByIDForUpdate gets a user by ID from the database and locks the row until the ambient transaction ends
+func (r *UserRepository) GetUserByIDForUpdate(ctx context.Context, id uint64) (*domain.User, error) {
+	query := r.db.QueryBuilder.Select(userColumns).
+		From("users").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		Limit(1).
+		Suffix("FOR UPDATE")
+
+	return r.getUser(ctx, query)
+}
+
+// getUser selects a single user with the query from the database
+func (r *UserRepository) getUser(ctx context.Context, query sq.SelectBuilder) (*domain.User, error) {
+	var user domain.User
+
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
+	err = r.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
 		&user.Password,
 		&user.Role,
+		&user.VerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Version,
+		&user.DeletedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -90,68 +117,67 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id uint64) (*domain.Us
 
 // GetUserByEmail gets a user by email from the database
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
-	var user domain.User
-
-	query := r.db.QueryBuilder.Select("*").
+	query := r.db.QueryBuilder.Select(userColumns).
 		From("users").
-		Where(sq.Eq{"email": email}).
+		Where(sq.Eq{"email": email, "deleted_at": nil}).
 		Limit(1)
 
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return nil, err
-	}
-
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, domain.ErrDataNotFound
-		}
-		return nil, err
-	}
-
-	return &user, nil
+	return r.getUser(ctx, query)
 }
 
 // ListUsers lists all users from the database
 func (r *UserRepository) ListUsers(ctx context.Context, offset, limit uint64) ([]*domain.User, error) {
-	var user domain.User
-	var users []*domain.User
-
-	query := r.db.QueryBuilder.Select("*").
+	query := r.db.QueryBuilder.Select(userColumns).
 		From("users").
+		Where(sq.Eq{"deleted_at": nil}).
 		OrderBy("id").
 		Limit(limit).
 		Offset((offset - 1) * limit)
 
+	return r.listUsers(ctx, query)
+}
+
+// ListDeletedUsers lists the deleted users that are not purged yet from the database, the most recently deleted first
+func (r *UserRepository) ListDeletedUsers(ctx context.Context, offset, limit uint64) ([]*domain.User, error) {
+	query := r.db.QueryBuilder.Select(userColumns).
+		From("users").
+		Where(sq.NotEq{"deleted_at": nil}).
+		OrderBy("deleted_at DESC", "id").
+		Limit(limit).
+		Offset((offset - 1) * limit)
+
+	return r.listUsers(ctx, query)
+}
+
+// listUsers selects the users with the query from the database
+func (r *UserRepository) listUsers(ctx context.Context, query sq.SelectBuilder) ([]*domain.User, error) {
+	var users []*domain.User
+
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.Query(ctx, sql, args...)
+	rows, err := r.db.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
+		var user domain.User
+
 		err := rows.Scan(
 			&user.ID,
 			&user.Name,
 			&user.Email,
 			&user.Password,
 			&user.Role,
+			&user.VerifiedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&user.Version,
+			&user.DeletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -163,7 +189,9 @@ func (r *UserRepository) ListUsers(ctx context.Context, offset, limit uint64) ([
 	return users, nil
 }
 
-// UpdateUser updates a user by ID in the database
+// UpdateUser updates a user by ID in the database and increments its version.
+// If the user has a version, it is only updated if the version is still current.
+// Changing the email clears its verification, the new email has to be verified again
 func (r *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	name := nullString(user.Name)
 	email := nullString(user.Email)
@@ -175,27 +203,46 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*do
 		Set("email", sq.Expr("COALESCE(?, email)", email)).
 		Set("password", sq.Expr("COALESCE(?, password)", password)).
 		Set("role", sq.Expr("COALESCE(?, role)", role)).
+		Set("verified_at", sq.Expr("CASE WHEN ? <> email THEN NULL ELSE COALESCE(?, verified_at) END", email, user.VerifiedAt)).
 		Set("updated_at", time.Now()).
-		Where(sq.Eq{"id": user.ID}).
-		Suffix("RETURNING *")
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": user.ID, "deleted_at": nil}).
+		Suffix("RETURNING " + userColumns)
+
+	// with a version the update is a compare-and-swap, it only applies to the version it was based on
+	if user.Version != 0 {
+		query = query.Where(sq.Eq{"version": user.Version})
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
+	err = r.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
 		&user.Password,
 		&user.Role,
+		&user.VerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Version,
+		&user.DeletedAt,
 	)
 	if err != nil {
-		if errCode := r.db.ErrorCode(err); errCode == "23505" {
+		if err == pgx.ErrNoRows {
+			if user.Version != 0 {
+				return nil, domain.ErrVersionMismatch
+			}
+			return nil, domain.ErrDataNotFound
+		}
+		switch r.db.ErrorCode(err) {
+		case "23505":
 			return nil, domain.ErrConflictingData
+		case "23503":
+			return nil, domain.ErrUnknownRole
 		}
 		return nil, err
 	}
@@ -203,20 +250,124 @@ func (r *UserRepository) UpdateUser(ctx context.Context, user *domain.User) (*do
 	return user, nil
 }
 
-// DeleteUser deletes a user by ID from the database
+// DeleteUser marks a user by ID as deleted in the database and increments its version
 func (r *UserRepository) DeleteUser(ctx context.Context, id uint64) error {
+	query := r.db.QueryBuilder.Update("users").
+		Set("deleted_at", sq.Expr("now()")).
+		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id, "deleted_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// RestoreUser clears the deletion of a deleted user by ID in the database and increments its version
+func (r *UserRepository) RestoreUser(ctx context.Context, id uint64) (*domain.User, error) {
+	var user domain.User
+
+	query := r.db.QueryBuilder.Update("users").
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + userColumns)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.VerifiedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Version,
+		&user.DeletedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrDataNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// PurgeUser permanently deletes a deleted user by ID from the database
+func (r *UserRepository) PurgeUser(ctx context.Context, id uint64) error {
 	query := r.db.QueryBuilder.Delete("users").
-		Where(sq.Eq{"id": id})
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil})
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.Exec(ctx, sql, args...)
+	result, err := r.db.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
 		return err
 	}
 
+	if result.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
 	return nil
 }
+
+// PurgeDeletedUsers permanently deletes the users deleted before the given time from the database
+// and returns their IDs
+func (r *UserRepository) PurgeDeletedUsers(ctx context.Context, before time.Time) ([]uint64, error) {
+	query := r.db.QueryBuilder.Delete("users").
+		Where(sq.NotEq{"deleted_at": nil}).
+		Where(sq.Lt{"deleted_at": before}).
+		Suffix("RETURNING id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint64
+
+	for rows.Next() {
+		var id uint64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -40,6 +40,23 @@ func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	return bytes, err
 }
 
+// Increment increments the counter in the redis database
+// and sets its expiry if the counter has just been created
+func (r *Redis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	if ttl > 0 {
+		pipe.ExpireNX(ctx, key, ttl)
+	}
+
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
 // Delete removes the value from the redis database
 func (r *Redis) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
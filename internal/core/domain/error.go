@@ -13,14 +13,68 @@ var (
 	ErrConflictingData = errors.New("data conflicts with existing data in unique column")
 	// ErrTokenDuration is an error for when the token duration format is invalid
 	ErrTokenDuration = errors.New("invalid token duration format")
+	// ErrTokenKey is an error for when the token key configuration is invalid
+	ErrTokenKey = errors.New("invalid token key configuration")
+	// ErrTokenPurpose is an error for when the token purpose is not supported
+	ErrTokenPurpose = errors.New("unsupported token purpose")
+	// ErrTokenAlgorithm is an error for when the token signing algorithm is not supported
+	ErrTokenAlgorithm = errors.New("unsupported token signing algorithm")
+	// ErrHashAlgorithm is an error for when the password hashing algorithm is not supported
+	ErrHashAlgorithm = errors.New("unsupported password hashing algorithm")
 	// ErrTokenCreation is an error for when the token creation fails
 	ErrTokenCreation = errors.New("error creating token")
 	// ErrExpiredToken is an error for when the access token is expired
 	ErrExpiredToken = errors.New("access token has expired")
 	// ErrInvalidToken is an error for when the access token is invalid
 	ErrInvalidToken = errors.New("access token is invalid")
+	// ErrRevokedToken is an error for when the access token has been revoked
+	ErrRevokedToken = errors.New("access token has been revoked")
+	// ErrInvalidRefreshToken is an error for when the refresh token is unknown, expired or revoked
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	// ErrRefreshTokenReused is an error for when an already rotated refresh token is used again
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	// ErrInvalidResetToken is an error for when the password reset token is invalid, expired or used
+	ErrInvalidResetToken = errors.New("password reset token is invalid or expired")
+	// ErrInvalidVerificationToken is an error for when the email verification token is invalid or expired
+	ErrInvalidVerificationToken = errors.New("email verification token is invalid or expired")
+	// ErrVerificationRateLimited is an error for when the verification email is requested again too soon
+	ErrVerificationRateLimited = errors.New("verification email has been sent recently, try again later")
+	// ErrEmailNotVerified is an error for when an unverified user tries to log in
+	ErrEmailNotVerified = errors.New("email address has not been verified")
+	// ErrWeakPassword is an error for when the password does not satisfy the password policy
+	ErrWeakPassword = errors.New("password does not satisfy the password policy")
 	// ErrInvalidCredentials is an error for when the credentials are invalid
 	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrAccountLocked is an error for when the account is locked after too many failed login attempts
+	ErrAccountLocked = errors.New("account is temporarily locked due to too many failed login attempts")
+	// ErrTooManyLoginAttempts is an error for when the login is attempted again too soon
+	ErrTooManyLoginAttempts = errors.New("too many login attempts, try again later")
+	// ErrInvalidMFAToken is an error for when the MFA challenge token is invalid, expired or used
+	ErrInvalidMFAToken = errors.New("MFA challenge token is invalid or expired")
+	// ErrInvalidMFACode is an error for when the TOTP or recovery code is invalid or has already been used
+	ErrInvalidMFACode = errors.New("MFA code is invalid")
+	// ErrMFAAlreadyEnabled is an error for when the user enrolls a TOTP authenticator while one is already enabled
+	ErrMFAAlreadyEnabled = errors.New("multi-factor authentication is already enabled")
+	// ErrMFANotEnabled is an error for when the user has no enabled or pending TOTP authenticator
+	ErrMFANotEnabled = errors.New("multi-factor authentication is not enabled")
+	// ErrMFARequired is an error for when a privileged permission is exercised without the second factor
+	ErrMFARequired = errors.New("multi-factor authentication is required")
+	// ErrInvalidCurrentPassword is an error for when the current password confirming a profile change is missing or wrong
+	ErrInvalidCurrentPassword = errors.New("current password is missing or invalid")
+	// ErrVersionMismatch is an error for when the user has been modified since the given version was read
+	ErrVersionMismatch = errors.New("user has been modified since it was read")
+	// ErrVersionRequired is an error for when the user is modified without the version it was read at
+	ErrVersionRequired = errors.New("the version of the user is required to modify it")
+	// ErrSelfRoleChange is an error for when users try to change their own role
+	ErrSelfRoleChange = errors.New("users can not change their own role")
+	// ErrUnknownRole is an error for when the user is assigned a role that does not exist
+	ErrUnknownRole = errors.New("role does not exist")
+	// ErrUnknownPermission is an error for when the role is granted a permission that does not exist
+	ErrUnknownPermission = errors.New("permission does not exist")
+	// ErrRoleInUse is an error for when a role that is still assigned to users is deleted
+	ErrRoleInUse = errors.New("role is assigned to users")
+	// ErrBuiltInRole is an error for when a built-in role is deleted or its permissions are changed
+	ErrBuiltInRole = errors.New("built-in role can not be deleted or have its permissions changed")
 	// ErrEmptyAuthorizationHeader is an error for when the authorization header is empty
 	ErrEmptyAuthorizationHeader = errors.New("authorization header is not provided")
 	// ErrInvalidAuthorizationHeader is an error for when the authorization header is invalid
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType is an enum for the kind of change an event announces
+type EventType string
+
+// EventType enum values
+const (
+	EventUserRegistered  EventType = "user.registered"
+	EventUserUpdated     EventType = "user.updated"
+	EventUserRoleChanged EventType = "user.role_changed"
+	EventUserDeleted     EventType = "user.deleted"
+	EventUserRestored    EventType = "user.restored"
+	EventUserPurged      EventType = "user.purged"
+	EventUserLoggedIn    EventType = "user.logged_in"
+)
+
+// Event is an entity that represents a change of a user announced to other services
+type Event struct {
+	ID         uuid.UUID
+	Type       EventType
+	UserID     uint64
+	Data       map[string]any
+	OccurredAt time.Time
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// MFAClaim is the access token claim set when the user has passed the second authentication factor
+const MFAClaim = "mfa"
+
+// TOTP is an entity that represents the TOTP authenticator of a user.
+// The authenticator is pending until the user confirms it with a valid code
+type TOTP struct {
+	UserID      uint64
+	Secret      string
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// TOTPEnrollment is an entity that represents a new TOTP authenticator to add to an authenticator app
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+	QRCode []byte
+}
+
+// MFAChallenge is an entity that represents the second login step.
+// The challenge token is exchanged for a token pair together with a valid TOTP or recovery code
+type MFAChallenge struct {
+	Token     []byte
+	ExpiresAt time.Time
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Notification is an entity that represents a message sent to a user
+type Notification struct {
+	To      string
+	Subject string
+	Body    string
+}
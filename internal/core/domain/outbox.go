@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// OutboxEntry is an entity that represents an event stored in the outbox until it is relayed
+type OutboxEntry struct {
+	ID            uint64
+	Event         *Event
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "strings"
+
+// PasswordRule is a rule of the password policy
+type PasswordRule string
+
+// PasswordRule enum values
+const (
+	PasswordRuleMinLength PasswordRule = "min_length"
+	PasswordRuleMaxLength PasswordRule = "max_length"
+	PasswordRuleUppercase PasswordRule = "uppercase"
+	PasswordRuleLowercase PasswordRule = "lowercase"
+	PasswordRuleDigit     PasswordRule = "digit"
+	PasswordRuleSymbol    PasswordRule = "symbol"
+	PasswordRuleDenylist  PasswordRule = "denylist"
+	PasswordRuleEmail     PasswordRule = "email"
+)
+
+// PasswordViolation is an entity that represents a password policy rule the password breaks
+type PasswordViolation struct {
+	Rule    PasswordRule
+	Message string
+}
+
+// PasswordPolicyError is an error for when the password breaks one or more rules of the password policy.
+// It matches ErrWeakPassword with errors.Is
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+// Error returns the messages of all the violations
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+
+	return ErrWeakPassword.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Is reports whether the target is ErrWeakPassword
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
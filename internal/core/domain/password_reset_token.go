@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken is an entity that represents a single-use password reset token.
+// Only the hash of the token is stored
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uint64
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// PublicKey is an entity that represents a public key
+// that other services use to verify tokens offline.
+// Key holds the raw Ed25519 public key or the PKIX, ASN.1 DER encoded RSA public key
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       []byte
+	ExpiresAt *time.Time
+}
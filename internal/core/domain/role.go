@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Action is an enum for the operation a permission allows
+type Action string
+
+// Action enum values
+const (
+	ActionRead        Action = "read"
+	ActionReadDetails Action = "read_details"
+	ActionCreate      Action = "create"
+	ActionUpdate      Action = "update"
+	ActionDelete      Action = "delete"
+	ActionUnlock      Action = "unlock"
+	ActionRevoke      Action = "revoke"
+	ActionRestore     Action = "restore"
+	ActionPurge       Action = "purge"
+)
+
+// Resource is an enum for the kind of data a permission applies to
+type Resource string
+
+// Resource enum values
+const (
+	ResourceUsers    Resource = "users"
+	ResourceSessions Resource = "sessions"
+	ResourceRoles    Resource = "roles"
+)
+
+// Permission is an entity that represents the right to perform the action on the resource
+type Permission struct {
+	ID          uint64
+	Resource    Resource
+	Action      Action
+	Description string
+}
+
+// String returns the permission in the "resource:action" format
+func (p Permission) String() string {
+	return string(p.Resource) + ":" + string(p.Action)
+}
+
+// RequiresMFA reports whether exercising the permission requires the second factor, when it is enforced.
+// Reading users does not, every change of users and sessions and everything about roles does
+func (p Permission) RequiresMFA() bool {
+	if p.Resource == ResourceRoles {
+		return true
+	}
+
+	return p.Action != ActionRead && p.Action != ActionReadDetails
+}
+
+// ParsePermission parses the permission in the "resource:action" format
+func ParsePermission(permission string) (Permission, error) {
+	resource, action, ok := strings.Cut(permission, ":")
+	if !ok || resource == "" || action == "" {
+		return Permission{}, ErrUnknownPermission
+	}
+
+	return Permission{
+		Resource: Resource(resource),
+		Action:   Action(action),
+	}, nil
+}
+
+// Role is an entity that represents a named set of permissions assigned to users
+type Role struct {
+	ID          uint64
+	Name        UserRole
+	Description string
+	Permissions []Permission
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Can reports whether the role has the permission to perform the action on the resource
+func (r *Role) Can(action Action, resource Resource) bool {
+	for _, permission := range r.Permissions {
+		if permission.Action == action && permission.Resource == resource {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanAll reports whether the role has every one of the permissions
+func (r *Role) CanAll(permissions []Permission) bool {
+	for _, permission := range permissions {
+		if !r.Can(permission.Action, permission.Resource) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BuiltIn reports whether the role is one of the roles the application relies on
+func (r *Role) BuiltIn() bool {
+	return r.Name == Admin || r.Name == Basic
+}
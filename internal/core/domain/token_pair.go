@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// TokenPair is an entity that represents an access token and the refresh token to renew it
+type TokenPair struct {
+	AccessToken      []byte
+	RefreshToken     []byte
+	ExpiresAt        time.Time
+	RefreshExpiresAt time.Time
+}
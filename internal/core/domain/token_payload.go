@@ -1,10 +1,36 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
 
-// TokenPayload is an entity that represents the payload of the token
+	"github.com/google/uuid"
+)
+
+// SessionClaim is the access token claim holding the session the token is issued for,
+// which is the family of the refresh tokens issued along with it
+const SessionClaim = "sid"
+
+// TokenPayload is an entity that represents the payload of the token.
+// Claims holds the additional application specific claims of the token
 type TokenPayload struct {
-	ID     uuid.UUID
-	UserID uint64
-	Role   UserRole
+	ID        uuid.UUID
+	UserID    uint64
+	Role      UserRole
+	Issuer    string
+	Audience  string
+	Claims    map[string]any
+	IssuedAt  time.Time
+	ExpiredAt time.Time
+}
+
+// MFA reports whether the token has been issued after the user passed the second authentication factor
+func (tp *TokenPayload) MFA() bool {
+	mfa, _ := tp.Claims[MFAClaim].(bool)
+	return mfa
+}
+
+// Session returns the session the token is issued for, it is empty if the token has no session claim
+func (tp *TokenPayload) Session() string {
+	session, _ := tp.Claims[SessionClaim].(string)
+	return session
 }
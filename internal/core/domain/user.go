@@ -11,13 +11,18 @@ const (
 	Basic UserRole = "basic"
 )
 
-// User is an entity that represents a user
+// User is an entity that represents a user.
+// The version is incremented on every update, so concurrent updates can be detected.
+// A deleted user keeps its data until it is purged, so it can be restored
 type User struct {
-	ID        uint64
-	Name      string
-	Email     string
-	Password  string
-	Role      UserRole
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID         uint64
+	Name       string
+	Email      string
+	Password   string
+	Role       UserRole
+	VerifiedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Version    uint64
+	DeletedAt  *time.Time
 }
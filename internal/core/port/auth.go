@@ -3,20 +3,57 @@ package port
 import (
 	"context"
 	"golang-hexagon/internal/core/domain"
+
+	"github.com/google/uuid"
 )
 
 //go:generate mockgen -source=auth.go -destination=mock/auth.go -package=mock
 
 // TokenService is an interface for interacting with token-related business logic
 type TokenService interface {
-	// CreateToken creates a new token for a given user
-	CreateToken(user *domain.User) ([]byte, error)
+	// CreateToken creates a new token with the additional claims for a given user and returns its payload
+	CreateToken(user *domain.User, claims map[string]any) ([]byte, *domain.TokenPayload, error)
 	// VerifyToken verifies the token and returns the payload
 	VerifyToken(token []byte) (*domain.TokenPayload, error)
+	// PublicKeys returns the public keys to verify tokens offline, if tokens are signed asymmetrically
+	PublicKeys() []domain.PublicKey
+}
+
+// RefreshTokenRepository is an interface for interacting with refresh token-related data
+type RefreshTokenRepository interface {
+	// CreateRefreshToken inserts a new refresh token into the database
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error)
+	// GetRefreshTokenByHash selects a refresh token by the hash of its value
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	// RotateRefreshToken marks an active refresh token as rotated
+	RotateRefreshToken(ctx context.Context, id uuid.UUID) error
+	// RevokeRefreshTokenFamily revokes all refresh tokens of the family
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	// RevokeUserRefreshTokens revokes all refresh tokens of the user
+	RevokeUserRefreshTokens(ctx context.Context, userID uint64) error
+	// RevokeOtherRefreshTokens revokes all refresh tokens of the user except those of the family
+	RevokeOtherRefreshTokens(ctx context.Context, userID uint64, familyID uuid.UUID) error
 }
 
 // AuthService is an interface for interacting with user authentication-related business logic
 type AuthService interface {
-	// Login authenticates a user by email and password and returns a token
-	Login(ctx context.Context, email, password string) ([]byte, error)
+	// Login authenticates a user by email and password and returns a token pair,
+	// or an MFA challenge if the user has enabled MFA.
+	// The source identifies the client, e.g. its IP address, to limit failed attempts from it
+	Login(ctx context.Context, email, password, source string) (*domain.TokenPair, *domain.MFAChallenge, error)
+	// VerifyMFA exchanges the MFA challenge token and a TOTP or recovery code for a token pair
+	VerifyMFA(ctx context.Context, challengeToken []byte, code, source string) (*domain.TokenPair, error)
+	// Refresh exchanges a refresh token for a new token pair
+	Refresh(ctx context.Context, refreshToken []byte) (*domain.TokenPair, error)
+	// VerifyToken verifies the access token and checks that it has not been revoked
+	VerifyToken(ctx context.Context, token []byte) (*domain.TokenPayload, error)
+	// Logout revokes the access token and the refresh token family if the refresh token is given
+	Logout(ctx context.Context, payload *domain.TokenPayload, refreshToken []byte) error
+	// RevokeUserTokens revokes all access and refresh tokens issued to the user
+	RevokeUserTokens(ctx context.Context, userID uint64) error
+	// RevokeOtherSessions revokes all access and refresh tokens issued to the user
+	// except those of the session the access token is issued for
+	RevokeOtherSessions(ctx context.Context, payload *domain.TokenPayload) error
+	// UnlockUser unlocks the account locked after too many failed login attempts
+	UnlockUser(ctx context.Context, userID uint64) error
 }
@@ -13,6 +13,9 @@ type CacheRepository interface {
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
 	// Get retrieves the value from the cache
 	Get(ctx context.Context, key string) ([]byte, error)
+	// Increment increments the counter in the cache and returns its new value.
+	// The ttl is applied only when the counter is created
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
 	// Delete removes the value from the cache
 	Delete(ctx context.Context, key string) error
 	// DeleteByPrefix removes the value from the cache with the given prefix
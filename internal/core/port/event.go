@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+)
+
+//go:generate mockgen -source=event.go -destination=mock/event.go -package=mock
+
+// EventPublisher is an interface for announcing domain events to other services
+type EventPublisher interface {
+	// Publish publishes the event
+	Publish(ctx context.Context, event *domain.Event) error
+}
@@ -0,0 +1,13 @@
+package port
+
+//go:generate mockgen -source=hasher.go -destination=mock/hasher.go -package=mock
+
+// PasswordHasher is an interface for hashing and verifying passwords
+type PasswordHasher interface {
+	// Hash hashes the password with the configured algorithm and parameters
+	Hash(password string) (string, error)
+	// Compare compares the password with the hash and returns an error if they do not match
+	Compare(password, hash string) error
+	// NeedsRehash reports whether the hash uses an outdated algorithm or parameters
+	NeedsRehash(hash string) bool
+}
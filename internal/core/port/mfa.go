@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+)
+
+//go:generate mockgen -source=mfa.go -destination=mock/mfa.go -package=mock
+
+type (
+	// TOTPService is an interface for generating and validating time-based one-time passwords
+	TOTPService interface {
+		// GenerateSecret generates a new secret for the account and returns it with its otpauth URI
+		GenerateSecret(accountName string) (secret, uri string, err error)
+		// QRCode encodes the otpauth URI as a QR code PNG image
+		QRCode(uri string) ([]byte, error)
+		// Validate checks the code against the secret at the current time
+		Validate(code, secret string) bool
+	}
+
+	// MFARepository is an interface for interacting with multi-factor authentication-related data
+	MFARepository interface {
+		// UpsertTOTP inserts a pending TOTP authenticator or replaces the existing one
+		UpsertTOTP(ctx context.Context, totp *domain.TOTP) (*domain.TOTP, error)
+		// GetTOTPByUserID selects the TOTP authenticator of the user
+		GetTOTPByUserID(ctx context.Context, userID uint64) (*domain.TOTP, error)
+		// ConfirmTOTP marks the pending TOTP authenticator of the user as confirmed
+		ConfirmTOTP(ctx context.Context, userID uint64) error
+		// DeleteTOTP deletes the TOTP authenticator and the recovery codes of the user
+		DeleteTOTP(ctx context.Context, userID uint64) error
+		// ReplaceRecoveryCodes replaces the recovery codes of the user with the given hashes
+		ReplaceRecoveryCodes(ctx context.Context, userID uint64, hashes []string) error
+		// UseRecoveryCode marks an unused recovery code of the user as used
+		UseRecoveryCode(ctx context.Context, userID uint64, hash string) error
+	}
+
+	// MFAService is an interface for interacting with multi-factor authentication-related business logic
+	MFAService interface {
+		// EnrollTOTP creates a pending TOTP authenticator for the user
+		EnrollTOTP(ctx context.Context, userID uint64) (*domain.TOTPEnrollment, error)
+		// ConfirmTOTP enables the pending TOTP authenticator with a valid code and returns the recovery codes
+		ConfirmTOTP(ctx context.Context, userID uint64, code string) ([]string, error)
+		// DisableTOTP removes the TOTP authenticator of the user with a valid TOTP or recovery code
+		DisableTOTP(ctx context.Context, userID uint64, code string) error
+		// RegenerateRecoveryCodes replaces the recovery codes of the user with a valid TOTP code
+		RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error)
+	}
+)
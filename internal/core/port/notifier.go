@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+)
+
+//go:generate mockgen -source=notifier.go -destination=mock/notifier.go -package=mock
+
+// Notifier is an interface for sending notifications to users
+type Notifier interface {
+	// Notify sends the notification
+	Notify(ctx context.Context, notification *domain.Notification) error
+}
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+	"time"
+)
+
+//go:generate mockgen -source=outbox.go -destination=mock/outbox.go -package=mock
+
+type (
+	// OutboxRepository is an interface for interacting with the events waiting to be relayed
+	OutboxRepository interface {
+		// Publish inserts the event into the outbox, as part of the ambient transaction if there is one
+		Publish(ctx context.Context, event *domain.Event) error
+		// ClaimPending selects and locks the pending entries that are due, skipping the ones locked by other relays
+		ClaimPending(ctx context.Context, limit uint64) ([]*domain.OutboxEntry, error)
+		// MarkSent marks an entry as relayed
+		MarkSent(ctx context.Context, id uint64) error
+		// MarkFailed records a failed attempt of an entry and when to attempt it again
+		MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error
+		// DeleteSent deletes the entries relayed before the given time
+		DeleteSent(ctx context.Context, before time.Time) (int64, error)
+	}
+
+	// OutboxService is an interface for relaying the events of the outbox
+	OutboxService interface {
+		// RelayPending publishes a batch of pending events and returns the number of events sent
+		RelayPending(ctx context.Context) (int, error)
+		// Cleanup deletes the events delivered longer ago than the retention and returns their number
+		Cleanup(ctx context.Context) (int64, error)
+	}
+)
@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+
+	"github.com/google/uuid"
+)
+
+//go:generate mockgen -source=password.go -destination=mock/password.go -package=mock
+
+type (
+	// PasswordResetRepository is an interface for interacting with password reset token-related data
+	PasswordResetRepository interface {
+		// CreatePasswordResetToken inserts a new password reset token into the database
+		CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error)
+		// GetPasswordResetTokenByHash selects a password reset token by the hash of its value
+		GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error)
+		// UsePasswordResetToken marks an unused password reset token as used
+		UsePasswordResetToken(ctx context.Context, id uuid.UUID) error
+		// InvalidateUserPasswordResetTokens marks all unused password reset tokens of the user as used
+		InvalidateUserPasswordResetTokens(ctx context.Context, userID uint64) error
+	}
+
+	// PasswordService is an interface for interacting with password reset-related business logic
+	PasswordService interface {
+		// ForgotPassword sends a password reset token to the user with the given email
+		ForgotPassword(ctx context.Context, email string) error
+		// ResetPassword sets a new password with the password reset token
+		ResetPassword(ctx context.Context, token []byte, password string) error
+	}
+)
@@ -0,0 +1,56 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+)
+
+//go:generate mockgen -source=role.go -destination=mock/role.go -package=mock
+
+type (
+	// RoleRepository is an interface for interacting with role and permission data
+	RoleRepository interface {
+		// CreateRole inserts a new role with its permissions into the database.
+		// It returns domain.ErrUnknownPermission if any of the permissions does not exist
+		CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+		// GetRoleByID selects a role with its permissions by id
+		GetRoleByID(ctx context.Context, id uint64) (*domain.Role, error)
+		// GetRoleByName selects a role with its permissions by name
+		GetRoleByName(ctx context.Context, name domain.UserRole) (*domain.Role, error)
+		// ListRoles selects a list of roles with their permissions with pagination
+		ListRoles(ctx context.Context, skip, limit uint64) ([]*domain.Role, error)
+		// UpdateRole updates the description of a role and replaces its permissions if they are not nil
+		UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+		// DeleteRole deletes a role. It returns domain.ErrRoleInUse if the role is assigned to users
+		DeleteRole(ctx context.Context, id uint64) error
+		// ListPermissions selects all permissions that can be granted to roles
+		ListPermissions(ctx context.Context) ([]domain.Permission, error)
+	}
+
+	// RoleService is an interface for managing roles and their permissions
+	RoleService interface {
+		// CreateRole creates a new role
+		CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+		// GetRole returns a role by id
+		GetRole(ctx context.Context, id uint64) (*domain.Role, error)
+		// ListRoles returns a list of roles with pagination
+		ListRoles(ctx context.Context, skip, limit uint64) ([]*domain.Role, error)
+		// UpdateRole updates the description and the permissions of a role
+		UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+		// DeleteRole deletes a role that is not assigned to any user
+		DeleteRole(ctx context.Context, id uint64) error
+		// ListPermissions returns all permissions that can be granted to roles
+		ListPermissions(ctx context.Context) ([]domain.Permission, error)
+	}
+
+	// Authorizer is an interface for checking the permissions of authenticated users
+	Authorizer interface {
+		// Can reports whether the subject is permitted to perform the action on the resource
+		Can(ctx context.Context, subject *domain.TokenPayload, action domain.Action, resource domain.Resource) (bool, error)
+		// CanGrant reports whether the subject holds every one of the permissions, so that it may grant them to a role
+		CanGrant(ctx context.Context, subject *domain.TokenPayload, permissions []domain.Permission) (bool, error)
+		// CanAssign reports whether the subject holds every permission of the role, so that it may assign the role to users.
+		// It returns domain.ErrUnknownRole if the role does not exist
+		CanAssign(ctx context.Context, subject *domain.TokenPayload, role domain.UserRole) (bool, error)
+	}
+)
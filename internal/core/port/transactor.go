@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+)
+
+//go:generate mockgen -source=transactor.go -destination=mock/transactor.go -package=mock
+
+// Transactor is an interface for running several repository calls as a single unit of work
+type Transactor interface {
+	// WithinTx runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise.
+	// Repositories called with the context passed to fn take part in the transaction
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
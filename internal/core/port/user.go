@@ -3,6 +3,7 @@ package port
 import (
 	"context"
 	"golang-hexagon/internal/core/domain"
+	"time"
 )
 
 //go:generate mockgen -source=user.go -destination=mock/user.go -package=mock
@@ -14,14 +15,25 @@ type (
 		CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 		// GetUserByID selects a user by id
 		GetUserByID(ctx context.Context, id uint64) (*domain.User, error)
+		// GetUserByIDForUpdate selects a user by id and locks it until the ambient transaction ends
+		GetUserByIDForUpdate(ctx context.Context, id uint64) (*domain.User, error)
 		// GetUserByEmail selects a user by email
 		GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 		// ListUsers selects a list of users with pagination
 		ListUsers(ctx context.Context, skip, limit uint64) ([]*domain.User, error)
-		// UpdateUser updates a user
+		// UpdateUser updates a user and increments its version, if the user has a version it has to be the current one.
+		// Changing the email clears the verification of the user
 		UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
-		// DeleteUser deletes a user
+		// DeleteUser marks a user as deleted, the queries above exclude deleted users
 		DeleteUser(ctx context.Context, id uint64) error
+		// ListDeletedUsers selects a list of deleted users with pagination
+		ListDeletedUsers(ctx context.Context, skip, limit uint64) ([]*domain.User, error)
+		// RestoreUser clears the deletion of a deleted user
+		RestoreUser(ctx context.Context, id uint64) (*domain.User, error)
+		// PurgeUser permanently deletes a deleted user
+		PurgeUser(ctx context.Context, id uint64) error
+		// PurgeDeletedUsers permanently deletes the users deleted before the given time and returns their ids
+		PurgeDeletedUsers(ctx context.Context, before time.Time) ([]uint64, error)
 	}
 
 	UserService interface {
@@ -31,9 +43,20 @@ type (
 		GetUser(ctx context.Context, id uint64) (*domain.User, error)
 		// ListUsers returns a list of users with pagination
 		ListUsers(ctx context.Context, offset, limit uint64) ([]*domain.User, error)
-		// UpdateUser updates a user
+		// UpdateUser updates a user, if the user has a version it has to be the current one
 		UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
-		// DeleteUser deletes a user
-		DeleteUser(ctx context.Context, id uint64) error
+		// UpdateProfile updates the user on their own behalf, checking the current password for sensitive changes.
+		// A password change revokes the other sessions of the user, the session of the access token is kept
+		UpdateProfile(ctx context.Context, payload *domain.TokenPayload, user *domain.User, currentPassword string) (*domain.User, error)
+		// DeleteUser deletes a user until it is restored or purged, a non-zero version has to be the current one
+		DeleteUser(ctx context.Context, id, version uint64) error
+		// ListDeletedUsers returns a list of deleted users with pagination
+		ListDeletedUsers(ctx context.Context, skip, limit uint64) ([]*domain.User, error)
+		// RestoreUser restores a deleted user
+		RestoreUser(ctx context.Context, id uint64) (*domain.User, error)
+		// PurgeUser permanently deletes a deleted user
+		PurgeUser(ctx context.Context, id uint64) error
+		// PurgeDeletedUsers permanently deletes the users deleted longer ago than the retention and returns their number
+		PurgeDeletedUsers(ctx context.Context) (int, error)
 	}
 )
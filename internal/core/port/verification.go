@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+)
+
+//go:generate mockgen -source=verification.go -destination=mock/verification.go -package=mock
+
+// VerificationService is an interface for interacting with email verification-related business logic
+type VerificationService interface {
+	// SendVerification sends an email verification token to the user
+	SendVerification(ctx context.Context, user *domain.User) error
+	// VerifyEmail marks the email of the user the verification token was issued to as verified
+	VerifyEmail(ctx context.Context, token []byte) error
+	// ResendVerification sends a new email verification token to the unverified user with the given email
+	ResendVerification(ctx context.Context, email string) error
+}
@@ -6,43 +6,674 @@ import (
 	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port"
 	"golang-hexagon/internal/core/util"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// LoginPolicy limits the failed login attempts.
+// An account is locked for LockoutDuration after MaxAttempts failures in a row,
+// a source is blocked after SourceMaxAttempts failures within LockoutDuration,
+// and every failure delays the next attempt on the account twice as long as the previous one, starting at Delay.
+// Users with unverified emails can not log in if RequireVerifiedEmail is set.
+// Users with enabled MFA have MFAChallengeDuration to complete the login with the second factor
+type LoginPolicy struct {
+	MaxAttempts          int64
+	SourceMaxAttempts    int64
+	LockoutDuration      time.Duration
+	Delay                time.Duration
+	RequireVerifiedEmail bool
+	MFAChallengeDuration time.Duration
+}
+
 // AuthService implements port.AuthService interface
-// and provides access to the user repository
-// and token service
+// and provides access to the user repository, password hasher, token service,
+// refresh token repository, MFA repository, TOTP service, cache service, event publisher and transactor
 type AuthService struct {
-	repo port.UserRepository
-	ts   port.TokenService
+	repo        port.UserRepository
+	hasher      port.PasswordHasher
+	ts          port.TokenService
+	tokenRepo   port.RefreshTokenRepository
+	mfaRepo     port.MFARepository
+	totp        port.TOTPService
+	cache       port.CacheRepository
+	events      port.EventPublisher
+	tx          port.Transactor
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	loginPolicy LoginPolicy
 }
 
 // NewAuthService creates a new auth service instance
-func NewAuthService(repo port.UserRepository, ts port.TokenService) *AuthService {
+func NewAuthService(
+	repo port.UserRepository,
+	hasher port.PasswordHasher,
+	ts port.TokenService,
+	tokenRepo port.RefreshTokenRepository,
+	mfaRepo port.MFARepository,
+	totp port.TOTPService,
+	cache port.CacheRepository,
+	events port.EventPublisher,
+	tx port.Transactor,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+	loginPolicy LoginPolicy,
+) *AuthService {
 	return &AuthService{
 		repo,
+		hasher,
 		ts,
+		tokenRepo,
+		mfaRepo,
+		totp,
+		cache,
+		events,
+		tx,
+		accessTTL,
+		refreshTTL,
+		loginPolicy,
 	}
 }
 
-// Login gives a registered user an access token if the credentials are valid
-func (as *AuthService) Login(ctx context.Context, email, password string) ([]byte, error) {
+// Login gives a registered user an access and refresh token pair if the credentials are valid.
+// Users with enabled MFA get an MFA challenge instead, to be completed with VerifyMFA.
+// Failed attempts are counted per account and per source to lock out brute-force attacks
+func (as *AuthService) Login(ctx context.Context, email, password, source string) (*domain.TokenPair, *domain.MFAChallenge, error) {
+	err := as.checkLoginAttempts(ctx, email, source)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	user, err := as.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, domain.ErrDataNotFound) {
-			return nil, domain.ErrInvalidCredentials
+			return nil, nil, as.failLogin(ctx, email, source)
+		}
+		return nil, nil, domain.ErrInternal
+	}
+
+	err = as.hasher.Compare(password, user.Password)
+	if err != nil {
+		return nil, nil, as.failLogin(ctx, email, source)
+	}
+
+	if as.hasher.NeedsRehash(user.Password) {
+		as.rehashPassword(ctx, user, password)
+	}
+
+	mfaEnabled, err := as.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, nil, domain.ErrInternal
+	}
+
+	// The failed attempts of users with enabled MFA are reset only after the second factor is passed
+	if !mfaEnabled {
+		err = as.resetLoginAttempts(ctx, email)
+		if err != nil {
+			return nil, nil, domain.ErrInternal
+		}
+	}
+
+	if as.loginPolicy.RequireVerifiedEmail && user.VerifiedAt == nil {
+		return nil, nil, domain.ErrEmailNotVerified
+	}
+
+	if mfaEnabled {
+		challenge, err := as.createMFAChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, challenge, nil
+	}
+
+	familyID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, domain.ErrTokenCreation
+	}
+
+	tokens, err := as.issueTokenPair(ctx, user, familyID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publishLoggedIn(ctx, as.events, user.ID, source, false)
+
+	return tokens, nil, nil
+}
+
+// VerifyMFA completes the login of a user with enabled MFA by exchanging the MFA challenge token
+// and a valid TOTP or recovery code for a token pair. Invalid codes count as failed login attempts
+func (as *AuthService) VerifyMFA(ctx context.Context, challengeToken []byte, code, source string) (*domain.TokenPair, error) {
+	cacheKey := util.GenerateCacheKey("mfa_challenge", util.HashToken(challengeToken))
+
+	cachedUserID, err := as.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInvalidMFAToken
+	}
+
+	var userID uint64
+
+	err = util.Deserialize(cachedUserID, &userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	user, err := as.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidMFAToken
 		}
 		return nil, domain.ErrInternal
 	}
 
-	err = util.ComparePassword(password, user.Password)
+	err = as.checkLoginAttempts(ctx, user.Email, source)
 	if err != nil {
-		return nil, domain.ErrInvalidCredentials
+		return nil, err
 	}
 
-	accessToken, err := as.ts.CreateToken(user)
+	err = verifyMFACode(ctx, as.mfaRepo, as.totp, as.cache, user.ID, code)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			return nil, err
+		}
+
+		err = as.failLogin(ctx, user.Email, source)
+		if errors.Is(err, domain.ErrAccountLocked) {
+			_ = as.cache.Delete(ctx, cacheKey)
+			return nil, err
+		}
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			return nil, domain.ErrInvalidMFACode
+		}
+		return nil, err
+	}
+
+	err = as.cache.Delete(ctx, cacheKey)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	err = as.resetLoginAttempts(ctx, user.Email)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	familyID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, domain.ErrTokenCreation
 	}
 
-	return []byte(accessToken), nil
+	tokens, err := as.issueTokenPair(ctx, user, familyID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	publishLoggedIn(ctx, as.events, user.ID, source, true)
+
+	return tokens, nil
+}
+
+// rehashPassword upgrades the stored password hash to the current algorithm and parameters.
+// Failures are ignored, the old hash stays valid and the upgrade is retried on the next login.
+// The update is announced with the change and increments the version of the user, so the cached user is dropped
+func (as *AuthService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	hashedPassword, err := as.hasher.Hash(password)
+	if err != nil {
+		return
+	}
+
+	err = withinTx(ctx, as.tx, func(ctx context.Context) error {
+		_, err := as.repo.UpdateUser(ctx, &domain.User{
+			ID:       user.ID,
+			Password: hashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		return publishEvent(ctx, as.events, domain.EventUserUpdated, user.ID, map[string]any{
+			"changed_fields": []string{"password"},
+		})
+	})
+	if err != nil {
+		return
+	}
+
+	_ = as.cache.Delete(ctx, util.GenerateCacheKey("user", user.ID))
+
+	user.Password = hashedPassword
+}
+
+// Refresh rotates the refresh token and gives a new token pair.
+// Reuse of an already rotated refresh token revokes the whole token family
+func (as *AuthService) Refresh(ctx context.Context, refreshToken []byte) (*domain.TokenPair, error) {
+	hash := util.HashToken(refreshToken)
+
+	token, err := as.tokenRepo.GetRefreshTokenByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	if token.RotatedAt != nil {
+		return nil, as.revokeFamily(ctx, token.FamilyID)
+	}
+
+	err = as.tokenRepo.RotateRefreshToken(ctx, token.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, as.revokeFamily(ctx, token.FamilyID)
+		}
+		return nil, domain.ErrInternal
+	}
+
+	user, err := as.repo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, domain.ErrInternal
+	}
+
+	// Enabling MFA revokes all sessions, so every session of a user with enabled MFA has passed the second factor
+	mfaEnabled, err := as.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return as.issueTokenPair(ctx, user, token.FamilyID, mfaEnabled)
+}
+
+// VerifyToken verifies the access token and checks it against the token and user denylists
+func (as *AuthService) VerifyToken(ctx context.Context, token []byte) (*domain.TokenPayload, error) {
+	payload, err := as.ts.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := util.GenerateCacheKey("revoked_token", payload.ID)
+	_, err = as.cache.Get(ctx, cacheKey)
+	if err == nil {
+		return nil, domain.ErrRevokedToken
+	}
+
+	cacheKey = util.GenerateCacheKey("revoked_user", payload.UserID)
+	cachedRevocation, err := as.cache.Get(ctx, cacheKey)
+	if err == nil {
+		var revocation userRevocation
+
+		err = util.Deserialize(cachedRevocation, &revocation)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		if revocation.revokes(payload) {
+			return nil, domain.ErrRevokedToken
+		}
+	}
+
+	return payload, nil
+}
+
+// Logout puts the access token on the denylist until it expires
+// and revokes the refresh token family if the refresh token is given
+func (as *AuthService) Logout(ctx context.Context, payload *domain.TokenPayload, refreshToken []byte) error {
+	ttl := time.Until(payload.ExpiredAt)
+	if ttl > 0 {
+		cacheKey := util.GenerateCacheKey("revoked_token", payload.ID)
+		userIDSerialized, err := util.Serialize(payload.UserID)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = as.cache.Set(ctx, cacheKey, userIDSerialized, ttl)
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	if len(refreshToken) == 0 {
+		return nil
+	}
+
+	token, err := as.tokenRepo.GetRefreshTokenByHash(ctx, util.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidRefreshToken
+		}
+		return domain.ErrInternal
+	}
+
+	if token.UserID != payload.UserID {
+		return domain.ErrInvalidRefreshToken
+	}
+
+	err = as.tokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// RevokeUserTokens revokes all access and refresh tokens issued to the user
+func (as *AuthService) RevokeUserTokens(ctx context.Context, userID uint64) error {
+	_, err := as.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	err = revokeUserAccessTokens(ctx, as.cache, userID, "", as.accessTTL)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = as.tokenRepo.RevokeUserRefreshTokens(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions revokes all access and refresh tokens issued to the user except those of the session
+// the access token is issued for. If the access token has no session, all tokens of the user are revoked
+func (as *AuthService) RevokeOtherSessions(ctx context.Context, payload *domain.TokenPayload) error {
+	keptSession := payload.Session()
+	familyID, err := uuid.Parse(keptSession)
+	if err != nil {
+		// the session of an access token without a valid session claim is unknown, so none is kept
+		keptSession = ""
+	}
+
+	err = revokeUserAccessTokens(ctx, as.cache, payload.UserID, keptSession, as.accessTTL)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if keptSession == "" {
+		err = as.tokenRepo.RevokeUserRefreshTokens(ctx, payload.UserID)
+	} else {
+		err = as.tokenRepo.RevokeOtherRefreshTokens(ctx, payload.UserID, familyID)
+	}
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// UnlockUser clears the failed login attempts of the user to unlock the account before the lockout expires
+func (as *AuthService) UnlockUser(ctx context.Context, userID uint64) error {
+	user, err := as.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	err = as.resetLoginAttempts(ctx, user.Email)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// checkLoginAttempts rejects the login if the account is locked, the source is blocked
+// or the delay after the last failed attempt has not passed yet
+func (as *AuthService) checkLoginAttempts(ctx context.Context, email, source string) error {
+	if as.loginFailures(ctx, loginFailuresKey(email)) >= as.loginPolicy.MaxAttempts {
+		return domain.ErrAccountLocked
+	}
+
+	if source != "" && as.loginFailures(ctx, sourceFailuresKey(source)) >= as.loginPolicy.SourceMaxAttempts {
+		return domain.ErrTooManyLoginAttempts
+	}
+
+	_, err := as.cache.Get(ctx, loginDelayKey(email))
+	if err == nil {
+		return domain.ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+// failLogin counts the failed login attempt, locks the account after too many failures
+// and delays the next attempt on the account
+func (as *AuthService) failLogin(ctx context.Context, email, source string) error {
+	cacheKey := loginFailuresKey(email)
+	failures, err := as.cache.Increment(ctx, cacheKey, as.loginPolicy.LockoutDuration)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if source != "" {
+		_, err = as.cache.Increment(ctx, sourceFailuresKey(source), as.loginPolicy.LockoutDuration)
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	failuresSerialized, err := util.Serialize(failures)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if failures >= as.loginPolicy.MaxAttempts {
+		// The lockout lasts for the whole duration starting from the last failure
+		err = as.cache.Set(ctx, cacheKey, failuresSerialized, as.loginPolicy.LockoutDuration)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		return domain.ErrAccountLocked
+	}
+
+	delay := as.loginPolicy.delay(failures)
+	if delay > 0 {
+		err = as.cache.Set(ctx, loginDelayKey(email), failuresSerialized, delay)
+		if err != nil {
+			return domain.ErrInternal
+		}
+	}
+
+	return domain.ErrInvalidCredentials
+}
+
+// resetLoginAttempts clears the failed login attempts of the account
+func (as *AuthService) resetLoginAttempts(ctx context.Context, email string) error {
+	err := as.cache.Delete(ctx, loginFailuresKey(email))
+	if err != nil {
+		return err
+	}
+
+	return as.cache.Delete(ctx, loginDelayKey(email))
+}
+
+// loginFailures returns the number of failed login attempts stored under the key.
+// Cache errors are treated as no failures
+func (as *AuthService) loginFailures(ctx context.Context, key string) int64 {
+	var failures int64
+
+	cachedFailures, err := as.cache.Get(ctx, key)
+	if err != nil {
+		return 0
+	}
+
+	err = util.Deserialize(cachedFailures, &failures)
+	if err != nil {
+		return 0
+	}
+
+	return failures
+}
+
+// delay returns the delay before the next login attempt after the given number of failures
+func (lp LoginPolicy) delay(failures int64) time.Duration {
+	if lp.Delay <= 0 || failures <= 0 {
+		return 0
+	}
+
+	delay := lp.Delay
+	for i := int64(1); i < failures && delay < lp.LockoutDuration; i++ {
+		delay *= 2
+	}
+
+	return min(delay, lp.LockoutDuration)
+}
+
+// loginFailuresKey returns the cache key of the failed login attempts of the account
+func loginFailuresKey(email string) string {
+	return util.GenerateCacheKey("login_failures", strings.ToLower(email))
+}
+
+// sourceFailuresKey returns the cache key of the failed login attempts from the source
+func sourceFailuresKey(source string) string {
+	return util.GenerateCacheKey("login_failures_source", source)
+}
+
+// loginDelayKey returns the cache key of the delay before the next login attempt on the account
+func loginDelayKey(email string) string {
+	return util.GenerateCacheKey("login_delay", strings.ToLower(email))
+}
+
+// mfaEnabled reports whether the user has a confirmed TOTP authenticator
+func (as *AuthService) mfaEnabled(ctx context.Context, userID uint64) (bool, error) {
+	_, err := confirmedTOTP(ctx, as.mfaRepo, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFANotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// createMFAChallenge stores a new MFA challenge token of the user for the challenge duration
+func (as *AuthService) createMFAChallenge(ctx context.Context, userID uint64) (*domain.MFAChallenge, error) {
+	token, err := util.GenerateToken()
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	userIDSerialized, err := util.Serialize(userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("mfa_challenge", util.HashToken(token))
+	ttl := as.loginPolicy.MFAChallengeDuration
+
+	err = as.cache.Set(ctx, cacheKey, userIDSerialized, ttl)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.MFAChallenge{
+		Token:     token,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// issueTokenPair creates an access token and stores a new refresh token of the given family.
+// The access token has the family as its session claim,
+// and the access token of a session authenticated with the second factor has the MFA claim
+func (as *AuthService) issueTokenPair(ctx context.Context, user *domain.User, familyID uuid.UUID, mfa bool) (*domain.TokenPair, error) {
+	claims := map[string]any{
+		domain.SessionClaim: familyID.String(),
+	}
+	if mfa {
+		claims[domain.MFAClaim] = true
+	}
+
+	accessToken, payload, err := as.ts.CreateToken(user, claims)
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	refreshToken, err := util.GenerateToken()
+	if err != nil {
+		return nil, domain.ErrTokenCreation
+	}
+
+	refreshExpiresAt := time.Now().Add(as.refreshTTL)
+
+	_, err = as.tokenRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		ID:        id,
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		TokenHash: util.HashToken(refreshToken),
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        payload.ExpiredAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// revokeFamily revokes the refresh token family after the token reuse is detected
+func (as *AuthService) revokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := as.tokenRepo.RevokeRefreshTokenFamily(ctx, familyID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return domain.ErrRefreshTokenReused
+}
+
+// userRevocation is put on the user denylist, it rejects the access tokens issued to the user
+// until it was revoked, except those of the kept session if there is one
+type userRevocation struct {
+	RevokedAt   int64  `json:"revoked_at"`
+	KeptSession string `json:"kept_session,omitempty"`
+}
+
+// revokes reports whether the access token is rejected by the revocation
+func (r userRevocation) revokes(payload *domain.TokenPayload) bool {
+	if payload.IssuedAt.After(time.Unix(r.RevokedAt, 0)) {
+		return false
+	}
+
+	return r.KeptSession == "" || r.KeptSession != payload.Session()
+}
+
+// revokeUserAccessTokens puts the user on the denylist
+// so that every access token issued to the user until now is rejected, except those of the kept session if it is given.
+// The entry expires with the access tokens it rejects
+func revokeUserAccessTokens(ctx context.Context, cache port.CacheRepository, userID uint64, keptSession string, ttl time.Duration) error {
+	cacheKey := util.GenerateCacheKey("revoked_user", userID)
+	revocationSerialized, err := util.Serialize(userRevocation{
+		RevokedAt:   time.Now().Unix(),
+		KeptSession: keptSession,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cache.Set(ctx, cacheKey, revocationSerialized, ttl)
 }
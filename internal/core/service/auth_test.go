@@ -3,31 +3,77 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"golang-hexagon/internal/adapter/publisher/memory"
 	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port/mock"
 	"golang-hexagon/internal/core/service"
 	"golang-hexagon/internal/core/util"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 type loginTestedInput struct {
-	email    string
-	password string
+	email                string
+	password             string
+	source               string
+	requireVerifiedEmail bool
 }
 
 type loginExpectedOutput struct {
-	token []byte
-	err   error
+	token     []byte
+	challenge bool
+	rehashed  bool
+	err       error
+}
+
+var loginPolicy = service.LoginPolicy{
+	MaxAttempts:          3,
+	SourceMaxAttempts:    10,
+	LockoutDuration:      time.Minute,
+	Delay:                time.Second,
+	MFAChallengeDuration: 5 * time.Minute,
+}
+
+// sessionClaims matches the claims of an access token with a session, along with the other expected claims
+type sessionClaims map[string]any
+
+func (m sessionClaims) Matches(x any) bool {
+	claims, ok := x.(map[string]any)
+	if !ok || len(claims) != len(m)+1 {
+		return false
+	}
+
+	session, _ := claims[domain.SessionClaim].(string)
+	if _, err := uuid.Parse(session); err != nil {
+		return false
+	}
+
+	for key, value := range m {
+		if claims[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m sessionClaims) String() string {
+	return fmt.Sprintf("has a session and the claims %v", map[string]any(m))
 }
 
 func TestAuthService_Login(t *testing.T) {
 	ctx := context.Background()
 	email := gofakeit.Email()
 	password := gofakeit.Password(true, true, true, true, false, 8)
-	hashedPassword, _ := util.HashPassword(password)
+	source := gofakeit.IPv4Address()
+	hashedPassword := gofakeit.UUID()
+	rehashedPassword := gofakeit.UUID()
 	user := &domain.User{
 		Email:    email,
 		Password: hashedPassword,
@@ -36,13 +82,39 @@ func TestAuthService_Login(t *testing.T) {
 		Email:    email,
 		Password: "wrong password",
 	}
+	verifiedAt := time.Now()
+	verifiedUser := &domain.User{
+		Email:      email,
+		Password:   hashedPassword,
+		VerifiedAt: &verifiedAt,
+	}
 	token := []byte(gofakeit.UUID())
+	tokenPayload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		ExpiredAt: time.Now().Add(15 * time.Minute).Truncate(time.Second),
+	}
+	failuresKey := util.GenerateCacheKey("login_failures", strings.ToLower(email))
+	sourceFailuresKey := util.GenerateCacheKey("login_failures_source", source)
+	delayKey := util.GenerateCacheKey("login_delay", strings.ToLower(email))
+	lockedFailures, _ := util.Serialize(loginPolicy.MaxAttempts)
+	userIDSerialized, _ := util.Serialize(user.ID)
+	confirmedAt := time.Now()
+	confirmedTOTP := &domain.TOTP{
+		UserID:      user.ID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	blockedFailures, _ := util.Serialize(loginPolicy.SourceMaxAttempts)
 
 	testCases := []struct {
 		desc  string
 		mocks func(
 			userRepo *mock.MockUserRepository,
+			hasher *mock.MockPasswordHasher,
 			tokenService *mock.MockTokenService,
+			refreshTokenRepo *mock.MockRefreshTokenRepository,
+			mfaRepo *mock.MockMFARepository,
+			cache *mock.MockCacheRepository,
 		)
 		input    loginTestedInput
 		expected loginExpectedOutput
@@ -51,40 +123,453 @@ func TestAuthService_Login(t *testing.T) {
 			desc: "Success",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
 			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Success_Rehash",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(&domain.User{
+						ID:       user.ID,
+						Email:    email,
+						Password: hashedPassword,
+					}, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(true)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(rehashedPassword, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(&domain.User{
+						ID:       user.ID,
+						Password: rehashedPassword,
+					})).
+					Times(1).
+					Return(&domain.User{}, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(util.GenerateCacheKey("user", user.ID))).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Any(), sessionClaims{}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token:    token,
+				rehashed: true,
+				err:      nil,
+			},
+		},
+		{
+			desc: "Success_RehashUpdateFailed",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(&domain.User{
+						ID:       user.ID,
+						Email:    email,
+						Password: hashedPassword,
+					}, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(true)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(rehashedPassword, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(&domain.User{
+						ID:       user.ID,
+						Password: rehashedPassword,
+					})).
+					Times(1).
+					Return(nil, domain.ErrInternal)
 				tokenService.EXPECT().
-					CreateToken(gomock.Eq(user)).
+					CreateToken(gomock.Any(), sessionClaims{}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Success_MFAChallenge",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Eq(userIDSerialized), gomock.Eq(loginPolicy.MFAChallengeDuration)).
+					DoAndReturn(func(_ context.Context, key string, _ []byte, _ time.Duration) error {
+						if !strings.HasPrefix(key, "mfa_challenge:") {
+							t.Errorf("expected to store the MFA challenge; got %q", key)
+						}
+						return nil
+					})
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token:     nil,
+				challenge: true,
+				err:       nil,
+			},
+		},
+		{
+			desc: "Fail_MFALookup",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
-					Return(token, nil)
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrInternal)
 			},
 			input: loginTestedInput{
 				email:    email,
 				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Success_VerifiedEmailRequired",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(verifiedUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(verifiedUser), sessionClaims{}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: loginTestedInput{
+				email:                email,
+				password:             password,
+				source:               source,
+				requireVerifiedEmail: true,
 			},
 			expected: loginExpectedOutput{
 				token: token,
 				err:   nil,
 			},
 		},
+		{
+			desc: "Fail_EmailNotVerified",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+			},
+			input: loginTestedInput{
+				email:                email,
+				password:             password,
+				source:               source,
+				requireVerifiedEmail: true,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrEmailNotVerified,
+			},
+		},
 		{
 			desc: "Fail_UserNotFound",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
 			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sourceFailuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(delayKey), gomock.Any(), gomock.Eq(time.Second)).
+					Return(nil)
 			},
 			input: loginTestedInput{
 				email:    email,
 				password: password,
+				source:   source,
 			},
 			expected: loginExpectedOutput{
 				token: nil,
@@ -95,16 +580,42 @@ func TestAuthService_Login(t *testing.T) {
 			desc: "Fail_PasswordMismatch",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
 			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
 					Return(failUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(failUser.Password)).
+					Return(domain.ErrInvalidCredentials)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(2), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sourceFailuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(2), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(delayKey), gomock.Any(), gomock.Eq(2*time.Second)).
+					Return(nil)
 			},
 			input: loginTestedInput{
 				email:    email,
 				password: password,
+				source:   source,
 			},
 			expected: loginExpectedOutput{
 				token: nil,
@@ -112,72 +623,2035 @@ func TestAuthService_Login(t *testing.T) {
 			},
 		},
 		{
-			desc: "Fail_TokenCreation",
+			desc: "Fail_PasswordMismatchWithoutSource",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
 			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
-					Return(user, nil)
-				tokenService.EXPECT().
-					CreateToken(gomock.Eq(user)).
+					Return(failUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(failUser.Password)).
+					Return(domain.ErrInvalidCredentials)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(delayKey), gomock.Any(), gomock.Eq(time.Second)).
+					Return(nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidCredentials,
+			},
+		},
+		{
+			desc: "Fail_LockedAfterFailure",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
-					Return(nil, domain.ErrTokenCreation)
+					Return(failUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(failUser.Password)).
+					Return(domain.ErrInvalidCredentials)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(loginPolicy.MaxAttempts, nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sourceFailuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(loginPolicy.MaxAttempts, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(lockedFailures), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(nil)
 			},
 			input: loginTestedInput{
 				email:    email,
 				password: password,
+				source:   source,
 			},
 			expected: loginExpectedOutput{
 				token: nil,
-				err:   domain.ErrTokenCreation,
+				err:   domain.ErrAccountLocked,
 			},
 		},
 		{
-			desc: "Fail_InternalError",
+			desc: "Fail_AccountLocked",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(lockedFailures, nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_SourceBlocked",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(blockedFailures, nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrTooManyLoginAttempts,
+			},
+		},
+		{
+			desc: "Fail_Delayed",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return([]byte("1"), nil)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrTooManyLoginAttempts,
+			},
+		},
+		{
+			desc: "Fail_CountFailure",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
 			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
 				userRepo.EXPECT().
 					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
 					Times(1).
-					Return(nil, domain.ErrInternal)
+					Return(failUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(failUser.Password)).
+					Return(domain.ErrInvalidCredentials)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(0), domain.ErrInternal)
 			},
 			input: loginTestedInput{
 				email:    email,
 				password: password,
+				source:   source,
 			},
 			expected: loginExpectedOutput{
 				token: nil,
 				err:   domain.ErrInternal,
 			},
 		},
-	}
-
-	for _, tc := range testCases {
-		tc := tc
-		t.Run(tc.desc, func(t *testing.T) {
-			t.Parallel()
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
+		{
+			desc: "Fail_ResetAttempts",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(domain.ErrInternal)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_TokenCreation",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{}).
+					Times(1).
+					Return(nil, nil, domain.ErrTokenCreation)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrTokenCreation,
+			},
+		},
+		{
+			desc: "Fail_RefreshTokenCreation",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(user, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(password), gomock.Eq(hashedPassword)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				hasher.EXPECT().
+					NeedsRehash(gomock.Eq(hashedPassword)).
+					Return(false)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, domain.ErrInternal)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(email)).
+					Times(1).
+					Return(nil, domain.ErrInternal)
+			},
+			input: loginTestedInput{
+				email:    email,
+				password: password,
+				source:   source,
+			},
+			expected: loginExpectedOutput{
+				token: nil,
+				err:   domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
 			userRepo := mock.NewMockUserRepository(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
 			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
 
-			tc.mocks(userRepo, tokenService)
+			tc.mocks(userRepo, hasher, tokenService, refreshTokenRepo, mfaRepo, cache)
 
-			authService := service.NewAuthService(userRepo, tokenService)
+			policy := loginPolicy
+			policy.RequireVerifiedEmail = tc.input.requireVerifiedEmail
 
-			token, err := authService.Login(ctx, tc.input.email, tc.input.password)
+			events := memory.New()
+
+			authService := service.NewAuthService(userRepo, hasher, tokenService, refreshTokenRepo, mfaRepo, mock.NewMockTOTPService(ctrl), cache, events, newTransactor(ctrl), accessTTL, time.Hour, policy)
+
+			tokens, challenge, err := authService.Login(ctx, tc.input.email, tc.input.password, tc.input.source)
 			if !errors.Is(err, tc.expected.err) {
 				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
 			}
+
+			published := events.Events()
+			if tc.expected.rehashed {
+				if len(published) == 0 || published[0].Type != domain.EventUserUpdated {
+					t.Errorf("[case: %s] expected to publish a %q event; got %v", tc.desc, domain.EventUserUpdated, published)
+				} else {
+					published = published[1:]
+				}
+			}
+			if tokens != nil && (len(published) != 1 || published[0].Type != domain.EventUserLoggedIn) {
+				t.Errorf("[case: %s] expected to publish a %q event; got %v", tc.desc, domain.EventUserLoggedIn, published)
+			}
+			if tokens == nil && len(published) != 0 {
+				t.Errorf("[case: %s] expected to publish no events; got %v", tc.desc, published)
+			}
+
+			if (challenge != nil) != tc.expected.challenge {
+				t.Errorf("[case: %s] expected to get an MFA challenge: %t; got %v", tc.desc, tc.expected.challenge, challenge)
+			}
+			if challenge != nil && len(challenge.Token) == 0 {
+				t.Errorf("[case: %s] expected to get an MFA challenge token", tc.desc)
+			}
+
+			var token []byte
+			if tokens != nil {
+				token = tokens.AccessToken
+				if len(tokens.RefreshToken) == 0 {
+					t.Errorf("[case: %s] expected to get a refresh token", tc.desc)
+				}
+				if !tokens.ExpiresAt.Equal(tokenPayload.ExpiredAt) {
+					t.Errorf("[case: %s] expected the access token to expire at %v; got %v", tc.desc, tokenPayload.ExpiredAt, tokens.ExpiresAt)
+				}
+				if !tokens.RefreshExpiresAt.After(tokens.ExpiresAt) {
+					t.Errorf("[case: %s] expected the refresh token to outlive the access token; got %v", tc.desc, tokens.RefreshExpiresAt)
+				}
+			}
 			if !reflect.DeepEqual(token, tc.expected.token) {
 				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.token, token)
 			}
 		})
 	}
 }
+
+type refreshTestedInput struct {
+	refreshToken []byte
+}
+
+type refreshExpectedOutput struct {
+	token []byte
+	err   error
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	refreshToken, _ := util.GenerateToken()
+	hash := util.HashToken(refreshToken)
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+		Role:  domain.Basic,
+	}
+	token := []byte(gofakeit.UUID())
+	tokenPayload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		ExpiredAt: time.Now().Add(15 * time.Minute).Truncate(time.Second),
+	}
+	rotatedAt := time.Now().Add(-time.Minute)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	activeToken := &domain.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	rotatedToken := &domain.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+		RotatedAt: &rotatedAt,
+	}
+	revokedToken := &domain.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+		RevokedAt: &revokedAt,
+	}
+	confirmedAt := time.Now()
+	confirmedTOTP := &domain.TOTP{
+		UserID:      user.ID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	expiredToken := &domain.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			tokenService *mock.MockTokenService,
+			refreshTokenRepo *mock.MockRefreshTokenRepository,
+			mfaRepo *mock.MockMFARepository,
+		)
+		input    refreshTestedInput
+		expected refreshExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(activeToken, nil)
+				refreshTokenRepo.EXPECT().
+					RotateRefreshToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						if rt.FamilyID != activeToken.FamilyID {
+							t.Errorf("expected the rotated token to keep family %s; got %s", activeToken.FamilyID, rt.FamilyID)
+						}
+						return rt, nil
+					})
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Success_MFA",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(activeToken, nil)
+				refreshTokenRepo.EXPECT().
+					RotateRefreshToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{domain.MFAClaim: true}).
+					Times(1).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Fail_MFALookup",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(activeToken, nil)
+				refreshTokenRepo.EXPECT().
+					RotateRefreshToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Times(1).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_TokenNotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidRefreshToken,
+			},
+		},
+		{
+			desc: "Fail_TokenExpired",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(expiredToken, nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidRefreshToken,
+			},
+		},
+		{
+			desc: "Fail_TokenRevoked",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(revokedToken, nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidRefreshToken,
+			},
+		},
+		{
+			desc: "Fail_TokenReused",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(rotatedToken, nil)
+				refreshTokenRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(rotatedToken.FamilyID)).
+					Times(1).
+					Return(nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrRefreshTokenReused,
+			},
+		},
+		{
+			desc: "Fail_ConcurrentRotation",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(activeToken, nil)
+				refreshTokenRepo.EXPECT().
+					RotateRefreshToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Times(1).
+					Return(domain.ErrDataNotFound)
+				refreshTokenRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(activeToken.FamilyID)).
+					Times(1).
+					Return(nil)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrRefreshTokenReused,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+			) {
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Times(1).
+					Return(nil, domain.ErrInternal)
+			},
+			input: refreshTestedInput{
+				refreshToken: refreshToken,
+			},
+			expected: refreshExpectedOutput{
+				token: nil,
+				err:   domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, tokenService, refreshTokenRepo, mfaRepo)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), tokenService, refreshTokenRepo, mfaRepo, mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			tokens, err := authService.Refresh(ctx, tc.input.refreshToken)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			var token []byte
+			if tokens != nil {
+				token = tokens.AccessToken
+			}
+			if !reflect.DeepEqual(token, tc.expected.token) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.token, token)
+			}
+		})
+	}
+}
+
+type verifyMFATestedInput struct {
+	challengeToken []byte
+	code           string
+	source         string
+}
+
+type verifyMFAExpectedOutput struct {
+	token []byte
+	err   error
+}
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	ctx := context.Background()
+	challengeToken, _ := util.GenerateToken()
+	challengeKey := util.GenerateCacheKey("mfa_challenge", util.HashToken(challengeToken))
+	code := gofakeit.DigitN(6)
+	source := gofakeit.IPv4Address()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+	}
+	userIDSerialized, _ := util.Serialize(user.ID)
+	confirmedAt := time.Now()
+	confirmedTOTP := &domain.TOTP{
+		UserID:      user.ID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	token := []byte(gofakeit.UUID())
+	tokenPayload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		ExpiredAt: time.Now().Add(15 * time.Minute).Truncate(time.Second),
+	}
+	failuresKey := util.GenerateCacheKey("login_failures", strings.ToLower(user.Email))
+	sourceFailuresKey := util.GenerateCacheKey("login_failures_source", source)
+	delayKey := util.GenerateCacheKey("login_delay", strings.ToLower(user.Email))
+	usedCodeKey := util.GenerateCacheKey("used_totp", fmt.Sprintf("%d-%s", user.ID, code))
+	lockedFailures, _ := util.Serialize(loginPolicy.MaxAttempts)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			tokenService *mock.MockTokenService,
+			refreshTokenRepo *mock.MockRefreshTokenRepository,
+			mfaRepo *mock.MockMFARepository,
+			totpService *mock.MockTOTPService,
+			cache *mock.MockCacheRepository,
+		)
+		input    verifyMFATestedInput
+		expected verifyMFAExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{domain.MFAClaim: true}).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Success_RecoveryCode",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(util.HashRecoveryCode(code))).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+				tokenService.EXPECT().
+					CreateToken(gomock.Eq(user), sessionClaims{domain.MFAClaim: true}).
+					Return(token, tokenPayload, nil)
+				refreshTokenRepo.EXPECT().
+					CreateRefreshToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, rt *domain.RefreshToken) (*domain.RefreshToken, error) {
+						return rt, nil
+					})
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: token,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Fail_InvalidChallenge",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidMFAToken,
+			},
+		},
+		{
+			desc: "Fail_UserNotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidMFAToken,
+			},
+		},
+		{
+			desc: "Fail_AccountLocked",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(lockedFailures, nil)
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: nil,
+				err:   domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_InvalidCode",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(util.HashRecoveryCode(code))).
+					Return(domain.ErrDataNotFound)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sourceFailuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(int64(1), nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(delayKey), gomock.Any(), gomock.Eq(loginPolicy.Delay)).
+					Return(nil)
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: nil,
+				err:   domain.ErrInvalidMFACode,
+			},
+		},
+		{
+			desc: "Fail_InvalidCodeLocksAccount",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(user.ID), gomock.Eq(util.HashRecoveryCode(code))).
+					Return(domain.ErrDataNotFound)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(loginPolicy.MaxAttempts, nil)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(sourceFailuresKey), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(loginPolicy.MaxAttempts, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(failuresKey), gomock.Eq(lockedFailures), gomock.Eq(loginPolicy.LockoutDuration)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(nil)
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: nil,
+				err:   domain.ErrAccountLocked,
+			},
+		},
+		{
+			desc: "Fail_MFANotEnabled",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				tokenService *mock.MockTokenService,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(challengeKey)).
+					Return(userIDSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(sourceFailuresKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil, domain.ErrDataNotFound)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: verifyMFATestedInput{
+				challengeToken: challengeToken,
+				code:           code,
+				source:         source,
+			},
+			expected: verifyMFAExpectedOutput{
+				token: nil,
+				err:   domain.ErrMFANotEnabled,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			totpService := mock.NewMockTOTPService(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, tokenService, refreshTokenRepo, mfaRepo, totpService, cache)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), tokenService, refreshTokenRepo, mfaRepo, totpService, cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			tokens, err := authService.VerifyMFA(ctx, tc.input.challengeToken, tc.input.code, tc.input.source)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			var token []byte
+			if tokens != nil {
+				token = tokens.AccessToken
+			}
+			if !reflect.DeepEqual(token, tc.expected.token) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.token, token)
+			}
+		})
+	}
+}
+
+type verifyTokenTestedInput struct {
+	token []byte
+}
+
+type verifyTokenExpectedOutput struct {
+	payload *domain.TokenPayload
+	err     error
+}
+
+func TestAuthService_VerifyToken(t *testing.T) {
+	ctx := context.Background()
+	token := []byte(gofakeit.UUID())
+	payload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		UserID:    gofakeit.Uint64(),
+		Role:      domain.Basic,
+		Claims:    map[string]any{domain.SessionClaim: uuid.NewString()},
+		IssuedAt:  time.Now().Add(-time.Minute).Truncate(time.Second),
+		ExpiredAt: time.Now().Add(time.Minute).Truncate(time.Second),
+	}
+
+	type userRevocation struct {
+		RevokedAt   int64  `json:"revoked_at"`
+		KeptSession string `json:"kept_session,omitempty"`
+	}
+
+	tokenCacheKey := util.GenerateCacheKey("revoked_token", payload.ID)
+	userCacheKey := util.GenerateCacheKey("revoked_user", payload.UserID)
+	revokedBefore, _ := util.Serialize(userRevocation{RevokedAt: payload.IssuedAt.Add(-time.Hour).Unix()})
+	revokedAfter, _ := util.Serialize(userRevocation{RevokedAt: payload.IssuedAt.Add(time.Second).Unix()})
+	revokedAfterKeepingSession, _ := util.Serialize(userRevocation{
+		RevokedAt:   payload.IssuedAt.Add(time.Second).Unix(),
+		KeptSession: payload.Session(),
+	})
+	revokedAfterKeepingOther, _ := util.Serialize(userRevocation{
+		RevokedAt:   payload.IssuedAt.Add(time.Second).Unix(),
+		KeptSession: uuid.NewString(),
+	})
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			tokenService *mock.MockTokenService,
+			cache *mock.MockCacheRepository,
+		)
+		input    verifyTokenTestedInput
+		expected verifyTokenExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(userCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: payload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_IssuedAfterUserRevocation",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(userCacheKey)).
+					Return(revokedBefore, nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: payload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_SessionKept",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(userCacheKey)).
+					Return(revokedAfterKeepingSession, nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: payload,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_InvalidToken",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(nil, domain.ErrInvalidToken)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrInvalidToken,
+			},
+		},
+		{
+			desc: "Fail_TokenRevoked",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return([]byte("1"), nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrRevokedToken,
+			},
+		},
+		{
+			desc: "Fail_UserTokensRevoked",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(userCacheKey)).
+					Return(revokedAfter, nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrRevokedToken,
+			},
+		},
+		{
+			desc: "Fail_OtherSessionKept",
+			mocks: func(
+				tokenService *mock.MockTokenService,
+				cache *mock.MockCacheRepository,
+			) {
+				tokenService.EXPECT().
+					VerifyToken(gomock.Eq(token)).
+					Return(payload, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(userCacheKey)).
+					Return(revokedAfterKeepingOther, nil)
+			},
+			input: verifyTokenTestedInput{
+				token: token,
+			},
+			expected: verifyTokenExpectedOutput{
+				payload: nil,
+				err:     domain.ErrRevokedToken,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(tokenService, cache)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), tokenService, refreshTokenRepo, mock.NewMockMFARepository(ctrl), mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			payload, err := authService.VerifyToken(ctx, tc.input.token)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+			if !reflect.DeepEqual(payload, tc.expected.payload) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.expected.payload, payload)
+			}
+		})
+	}
+}
+
+type logoutTestedInput struct {
+	payload      *domain.TokenPayload
+	refreshToken []byte
+}
+
+type logoutExpectedOutput struct {
+	err error
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctx := context.Background()
+	refreshToken, _ := util.GenerateToken()
+	hash := util.HashToken(refreshToken)
+	payload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		UserID:    gofakeit.Uint64(),
+		Role:      domain.Basic,
+		IssuedAt:  time.Now().Add(-time.Minute),
+		ExpiredAt: time.Now().Add(time.Hour),
+	}
+	expiredPayload := &domain.TokenPayload{
+		ID:        uuid.New(),
+		UserID:    payload.UserID,
+		Role:      domain.Basic,
+		IssuedAt:  time.Now().Add(-time.Hour),
+		ExpiredAt: time.Now().Add(-time.Minute),
+	}
+	ownToken := &domain.RefreshToken{
+		ID:       uuid.New(),
+		FamilyID: uuid.New(),
+		UserID:   payload.UserID,
+	}
+	foreignToken := &domain.RefreshToken{
+		ID:       uuid.New(),
+		FamilyID: uuid.New(),
+		UserID:   payload.UserID + 1,
+	}
+
+	tokenCacheKey := util.GenerateCacheKey("revoked_token", payload.ID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			refreshTokenRepo *mock.MockRefreshTokenRepository,
+			cache *mock.MockCacheRepository,
+		)
+		input    logoutTestedInput
+		expected logoutExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(tokenCacheKey), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			input: logoutTestedInput{
+				payload: payload,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_WithRefreshToken",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(tokenCacheKey), gomock.Any(), gomock.Any()).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(ownToken, nil)
+				refreshTokenRepo.EXPECT().
+					RevokeRefreshTokenFamily(gomock.Any(), gomock.Eq(ownToken.FamilyID)).
+					Return(nil)
+			},
+			input: logoutTestedInput{
+				payload:      payload,
+				refreshToken: refreshToken,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_ExpiredToken",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+			},
+			input: logoutTestedInput{
+				payload: expiredPayload,
+			},
+			expected: logoutExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_ForeignRefreshToken",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(tokenCacheKey), gomock.Any(), gomock.Any()).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					GetRefreshTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(foreignToken, nil)
+			},
+			input: logoutTestedInput{
+				payload:      payload,
+				refreshToken: refreshToken,
+			},
+			expected: logoutExpectedOutput{
+				err: domain.ErrInvalidRefreshToken,
+			},
+		},
+		{
+			desc: "Fail_SetCache",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(tokenCacheKey), gomock.Any(), gomock.Any()).
+					Return(domain.ErrInternal)
+			},
+			input: logoutTestedInput{
+				payload: payload,
+			},
+			expected: logoutExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(refreshTokenRepo, cache)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), tokenService, refreshTokenRepo, mock.NewMockMFARepository(ctrl), mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			err := authService.Logout(ctx, tc.input.payload, tc.input.refreshToken)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
+
+type revokeUserTokensTestedInput struct {
+	id uint64
+}
+
+type revokeUserTokensExpectedOutput struct {
+	err error
+}
+
+func TestAuthService_RevokeUserTokens(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	cacheKey := util.GenerateCacheKey("revoked_user", userID)
+	ttl := accessTTL
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			refreshTokenRepo *mock.MockRefreshTokenRepository,
+			cache *mock.MockCacheRepository,
+		)
+		input    revokeUserTokensTestedInput
+		expected revokeUserTokensExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID}, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			input: revokeUserTokensTestedInput{
+				id: userID,
+			},
+			expected: revokeUserTokensExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: revokeUserTokensTestedInput{
+				id: userID,
+			},
+			expected: revokeUserTokensExpectedOutput{
+				err: domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_RevokeRefreshTokens",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID}, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(domain.ErrInternal)
+			},
+			input: revokeUserTokensTestedInput{
+				id: userID,
+			},
+			expected: revokeUserTokensExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, refreshTokenRepo, cache)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), tokenService, refreshTokenRepo, mock.NewMockMFARepository(ctrl), mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			err := authService.RevokeUserTokens(ctx, tc.input.id)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
+
+type revokeOtherSessionsTestedInput struct {
+	payload *domain.TokenPayload
+}
+
+type revokeOtherSessionsExpectedOutput struct {
+	err error
+}
+
+func TestAuthService_RevokeOtherSessions(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	familyID := uuid.New()
+	cacheKey := util.GenerateCacheKey("revoked_user", userID)
+	ttl := accessTTL
+
+	payload := &domain.TokenPayload{
+		ID:     uuid.New(),
+		UserID: userID,
+		Role:   domain.Basic,
+		Claims: map[string]any{domain.SessionClaim: familyID.String()},
+	}
+	sessionlessPayload := &domain.TokenPayload{
+		ID:     uuid.New(),
+		UserID: userID,
+		Role:   domain.Basic,
+	}
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			refreshTokenRepo *mock.MockRefreshTokenRepository,
+			cache *mock.MockCacheRepository,
+		)
+		input    revokeOtherSessionsTestedInput
+		expected revokeOtherSessionsExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					RevokeOtherRefreshTokens(gomock.Any(), gomock.Eq(userID), gomock.Eq(familyID)).
+					Return(nil)
+			},
+			input: revokeOtherSessionsTestedInput{
+				payload: payload,
+			},
+			expected: revokeOtherSessionsExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_NoSession",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			input: revokeOtherSessionsTestedInput{
+				payload: sessionlessPayload,
+			},
+			expected: revokeOtherSessionsExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_CacheSet",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(domain.ErrInternal)
+			},
+			input: revokeOtherSessionsTestedInput{
+				payload: payload,
+			},
+			expected: revokeOtherSessionsExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_RevokeRefreshTokens",
+			mocks: func(
+				refreshTokenRepo *mock.MockRefreshTokenRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				refreshTokenRepo.EXPECT().
+					RevokeOtherRefreshTokens(gomock.Any(), gomock.Eq(userID), gomock.Eq(familyID)).
+					Return(domain.ErrInternal)
+			},
+			input: revokeOtherSessionsTestedInput{
+				payload: payload,
+			},
+			expected: revokeOtherSessionsExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(refreshTokenRepo, cache)
+
+			authService := service.NewAuthService(mock.NewMockUserRepository(ctrl), mock.NewMockPasswordHasher(ctrl), mock.NewMockTokenService(ctrl), refreshTokenRepo, mock.NewMockMFARepository(ctrl), mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			err := authService.RevokeOtherSessions(ctx, tc.input.payload)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
+
+type unlockUserTestedInput struct {
+	id uint64
+}
+
+type unlockUserExpectedOutput struct {
+	err error
+}
+
+func TestAuthService_UnlockUser(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+	}
+	failuresKey := util.GenerateCacheKey("login_failures", strings.ToLower(user.Email))
+	delayKey := util.GenerateCacheKey("login_delay", strings.ToLower(user.Email))
+
+	testCases := []struct {
+		desc     string
+		mocks    func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository)
+		input    unlockUserTestedInput
+		expected unlockUserExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(delayKey)).
+					Return(nil)
+			},
+			input: unlockUserTestedInput{
+				id: user.ID,
+			},
+			expected: unlockUserExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: unlockUserTestedInput{
+				id: user.ID,
+			},
+			expected: unlockUserExpectedOutput{
+				err: domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_DeleteCache",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(failuresKey)).
+					Return(domain.ErrInternal)
+			},
+			input: unlockUserTestedInput{
+				id: user.ID,
+			},
+			expected: unlockUserExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tokenService := mock.NewMockTokenService(ctrl)
+			refreshTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, cache)
+
+			authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), tokenService, refreshTokenRepo, mock.NewMockMFARepository(ctrl), mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+
+			err := authService.UnlockUser(ctx, tc.input.id)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
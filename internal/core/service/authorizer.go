@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"golang-hexagon/internal/core/util"
+)
+
+// Authorizer implements port.Authorizer interface
+// and provides access to the role repository and cache service
+type Authorizer struct {
+	repo  port.RoleRepository
+	cache port.CacheRepository
+}
+
+// NewAuthorizer creates a new authorizer instance
+func NewAuthorizer(repo port.RoleRepository, cache port.CacheRepository) *Authorizer {
+	return &Authorizer{
+		repo,
+		cache,
+	}
+}
+
+// Can reports whether the role of the subject grants the permission to perform the action on the resource.
+// A subject with a role that does not exist has no permissions
+func (a *Authorizer) Can(ctx context.Context, subject *domain.TokenPayload, action domain.Action, resource domain.Resource) (bool, error) {
+	if subject == nil {
+		return false, nil
+	}
+
+	role, err := a.getRole(ctx, subject.Role)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return false, nil
+		}
+		return false, domain.ErrInternal
+	}
+
+	return role.Can(action, resource), nil
+}
+
+// CanGrant reports whether the role of the subject grants every one of the permissions,
+// a subject can not grant permissions it does not hold itself
+func (a *Authorizer) CanGrant(ctx context.Context, subject *domain.TokenPayload, permissions []domain.Permission) (bool, error) {
+	if subject == nil {
+		return false, nil
+	}
+
+	role, err := a.getRole(ctx, subject.Role)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return false, nil
+		}
+		return false, domain.ErrInternal
+	}
+
+	return role.CanAll(permissions), nil
+}
+
+// CanAssign reports whether the role of the subject grants every permission of the role to assign,
+// so that a subject can not assign a role more privileged than its own
+func (a *Authorizer) CanAssign(ctx context.Context, subject *domain.TokenPayload, role domain.UserRole) (bool, error) {
+	assignedRole, err := a.getRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return false, domain.ErrUnknownRole
+		}
+		return false, domain.ErrInternal
+	}
+
+	return a.CanGrant(ctx, subject, assignedRole.Permissions)
+}
+
+// getRole gets the role with its permissions from the cache or the repository
+func (a *Authorizer) getRole(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	var role *domain.Role
+
+	cacheKey := roleCacheKey(name)
+	cachedRole, err := a.cache.Get(ctx, cacheKey)
+	if err == nil {
+		err := util.Deserialize(cachedRole, &role)
+		if err != nil {
+			return nil, err
+		}
+		return role, nil
+	}
+
+	role, err = a.repo.GetRoleByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	roleSerialized, err := util.Serialize(role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.cache.Set(ctx, cacheKey, roleSerialized, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+// roleCacheKey returns the cache key of the role with its permissions
+func roleCacheKey(name domain.UserRole) string {
+	return util.GenerateCacheKey("role", name)
+}
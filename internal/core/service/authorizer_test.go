@@ -0,0 +1,467 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+	"golang-hexagon/internal/core/util"
+	"testing"
+)
+
+type canTestedInput struct {
+	subject  *domain.TokenPayload
+	action   domain.Action
+	resource domain.Resource
+}
+
+type canExpectedOutput struct {
+	allowed bool
+	err     error
+}
+
+func TestAuthorizer_Can(t *testing.T) {
+	ctx := context.Background()
+	subject := &domain.TokenPayload{
+		UserID: gofakeit.Uint64(),
+		Role:   domain.UserRole("support"),
+	}
+	role := &domain.Role{
+		ID:   gofakeit.Uint64(),
+		Name: subject.Role,
+		Permissions: []domain.Permission{
+			{Resource: domain.ResourceUsers, Action: domain.ActionRead},
+			{Resource: domain.ResourceUsers, Action: domain.ActionUnlock},
+		},
+	}
+	cacheKey := util.GenerateCacheKey("role", subject.Role)
+	roleSerialized, _ := util.Serialize(role)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository)
+		input    canTestedInput
+		expected canExpectedOutput
+	}{
+		{
+			desc: "Success_FromCache",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(roleSerialized, nil)
+			},
+			input: canTestedInput{
+				subject:  subject,
+				action:   domain.ActionUnlock,
+				resource: domain.ResourceUsers,
+			},
+			expected: canExpectedOutput{
+				allowed: true,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_FromRepository",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				roleRepo.EXPECT().
+					GetRoleByName(gomock.Any(), gomock.Eq(subject.Role)).
+					Return(role, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq(roleSerialized), gomock.Any()).
+					Return(nil)
+			},
+			input: canTestedInput{
+				subject:  subject,
+				action:   domain.ActionRead,
+				resource: domain.ResourceUsers,
+			},
+			expected: canExpectedOutput{
+				allowed: true,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_Denied",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(roleSerialized, nil)
+			},
+			input: canTestedInput{
+				subject:  subject,
+				action:   domain.ActionDelete,
+				resource: domain.ResourceUsers,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_UnknownRole",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				roleRepo.EXPECT().
+					GetRoleByName(gomock.Any(), gomock.Eq(subject.Role)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: canTestedInput{
+				subject:  subject,
+				action:   domain.ActionRead,
+				resource: domain.ResourceUsers,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+				err:     nil,
+			},
+		},
+		{
+			desc:  "Success_NoSubject",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {},
+			input: canTestedInput{
+				subject:  nil,
+				action:   domain.ActionRead,
+				resource: domain.ResourceUsers,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				roleRepo.EXPECT().
+					GetRoleByName(gomock.Any(), gomock.Eq(subject.Role)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: canTestedInput{
+				subject:  subject,
+				action:   domain.ActionRead,
+				resource: domain.ResourceUsers,
+			},
+			expected: canExpectedOutput{
+				allowed: false,
+				err:     domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roleRepo := mock.NewMockRoleRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(roleRepo, cache)
+
+			authorizer := service.NewAuthorizer(roleRepo, cache)
+
+			allowed, err := authorizer.Can(ctx, tc.input.subject, tc.input.action, tc.input.resource)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if allowed != tc.expected.allowed {
+				t.Errorf("[case: %s] expected to get %t; got %t", tc.desc, tc.expected.allowed, allowed)
+			}
+		})
+	}
+}
+
+type canGrantTestedInput struct {
+	subject     *domain.TokenPayload
+	permissions []domain.Permission
+}
+
+type canGrantExpectedOutput struct {
+	allowed bool
+	err     error
+}
+
+func TestAuthorizer_CanGrant(t *testing.T) {
+	ctx := context.Background()
+	subject := &domain.TokenPayload{
+		UserID: gofakeit.Uint64(),
+		Role:   domain.UserRole("support"),
+	}
+	role := &domain.Role{
+		ID:   gofakeit.Uint64(),
+		Name: subject.Role,
+		Permissions: []domain.Permission{
+			{Resource: domain.ResourceUsers, Action: domain.ActionRead},
+			{Resource: domain.ResourceUsers, Action: domain.ActionUnlock},
+		},
+	}
+	cacheKey := util.GenerateCacheKey("role", subject.Role)
+	roleSerialized, _ := util.Serialize(role)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository)
+		input    canGrantTestedInput
+		expected canGrantExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(roleSerialized, nil)
+			},
+			input: canGrantTestedInput{
+				subject:     subject,
+				permissions: role.Permissions[1:],
+			},
+			expected: canGrantExpectedOutput{
+				allowed: true,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_Denied",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(roleSerialized, nil)
+			},
+			input: canGrantTestedInput{
+				subject: subject,
+				permissions: []domain.Permission{
+					{Resource: domain.ResourceUsers, Action: domain.ActionRead},
+					{Resource: domain.ResourceUsers, Action: domain.ActionPurge},
+				},
+			},
+			expected: canGrantExpectedOutput{
+				allowed: false,
+				err:     nil,
+			},
+		},
+		{
+			desc:  "Success_NoSubject",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {},
+			input: canGrantTestedInput{
+				subject:     nil,
+				permissions: role.Permissions,
+			},
+			expected: canGrantExpectedOutput{
+				allowed: false,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				roleRepo.EXPECT().
+					GetRoleByName(gomock.Any(), gomock.Eq(subject.Role)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: canGrantTestedInput{
+				subject:     subject,
+				permissions: role.Permissions,
+			},
+			expected: canGrantExpectedOutput{
+				allowed: false,
+				err:     domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roleRepo := mock.NewMockRoleRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(roleRepo, cache)
+
+			authorizer := service.NewAuthorizer(roleRepo, cache)
+
+			allowed, err := authorizer.CanGrant(ctx, tc.input.subject, tc.input.permissions)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if allowed != tc.expected.allowed {
+				t.Errorf("[case: %s] expected to get %t; got %t", tc.desc, tc.expected.allowed, allowed)
+			}
+		})
+	}
+}
+
+type canAssignTestedInput struct {
+	subject *domain.TokenPayload
+	role    domain.UserRole
+}
+
+type canAssignExpectedOutput struct {
+	allowed bool
+	err     error
+}
+
+func TestAuthorizer_CanAssign(t *testing.T) {
+	ctx := context.Background()
+	subject := &domain.TokenPayload{
+		UserID: gofakeit.Uint64(),
+		Role:   domain.UserRole("support"),
+	}
+	subjectRole := &domain.Role{
+		ID:   gofakeit.Uint64(),
+		Name: subject.Role,
+		Permissions: []domain.Permission{
+			{Resource: domain.ResourceUsers, Action: domain.ActionRead},
+			{Resource: domain.ResourceUsers, Action: domain.ActionUpdate},
+		},
+	}
+	basicRole := &domain.Role{
+		ID:          gofakeit.Uint64(),
+		Name:        domain.Basic,
+		Permissions: subjectRole.Permissions[:1],
+	}
+	adminRole := &domain.Role{
+		ID:   gofakeit.Uint64(),
+		Name: domain.Admin,
+		Permissions: append([]domain.Permission{
+			{Resource: domain.ResourceRoles, Action: domain.ActionUpdate},
+		}, subjectRole.Permissions...),
+	}
+	subjectCacheKey := util.GenerateCacheKey("role", subject.Role)
+	subjectRoleSerialized, _ := util.Serialize(subjectRole)
+	basicCacheKey := util.GenerateCacheKey("role", domain.Basic)
+	basicRoleSerialized, _ := util.Serialize(basicRole)
+	adminCacheKey := util.GenerateCacheKey("role", domain.Admin)
+	adminRoleSerialized, _ := util.Serialize(adminRole)
+	unknownRole := domain.UserRole("unknown")
+	unknownCacheKey := util.GenerateCacheKey("role", unknownRole)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository)
+		input    canAssignTestedInput
+		expected canAssignExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(basicCacheKey)).
+					Return(basicRoleSerialized, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(subjectCacheKey)).
+					Return(subjectRoleSerialized, nil)
+			},
+			input: canAssignTestedInput{
+				subject: subject,
+				role:    domain.Basic,
+			},
+			expected: canAssignExpectedOutput{
+				allowed: true,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Success_Denied",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(adminCacheKey)).
+					Return(adminRoleSerialized, nil)
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(subjectCacheKey)).
+					Return(subjectRoleSerialized, nil)
+			},
+			input: canAssignTestedInput{
+				subject: subject,
+				role:    domain.Admin,
+			},
+			expected: canAssignExpectedOutput{
+				allowed: false,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_UnknownRole",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(unknownCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				roleRepo.EXPECT().
+					GetRoleByName(gomock.Any(), gomock.Eq(unknownRole)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: canAssignTestedInput{
+				subject: subject,
+				role:    unknownRole,
+			},
+			expected: canAssignExpectedOutput{
+				allowed: false,
+				err:     domain.ErrUnknownRole,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(adminCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				roleRepo.EXPECT().
+					GetRoleByName(gomock.Any(), gomock.Eq(domain.Admin)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: canAssignTestedInput{
+				subject: subject,
+				role:    domain.Admin,
+			},
+			expected: canAssignExpectedOutput{
+				allowed: false,
+				err:     domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roleRepo := mock.NewMockRoleRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(roleRepo, cache)
+
+			authorizer := service.NewAuthorizer(roleRepo, cache)
+
+			allowed, err := authorizer.CanAssign(ctx, tc.input.subject, tc.input.role)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if allowed != tc.expected.allowed {
+				t.Errorf("[case: %s] expected to get %t; got %t", tc.desc, tc.expected.allowed, allowed)
+			}
+		})
+	}
+}
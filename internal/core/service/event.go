@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// publishEvent announces the change of the user.
+// Called within the transaction of the change, a failed announcement rolls the change back
+func publishEvent(ctx context.Context, events port.EventPublisher, eventType domain.EventType, userID uint64, data map[string]any) error {
+	return events.Publish(ctx, &domain.Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		UserID:     userID,
+		Data:       data,
+		OccurredAt: time.Now(),
+	})
+}
+
+// publishLoggedIn announces the login of the user from the source.
+// The tokens are already issued, so a failed announcement is not an error of the login
+func publishLoggedIn(ctx context.Context, events port.EventPublisher, userID uint64, source string, mfa bool) {
+	_ = publishEvent(ctx, events, domain.EventUserLoggedIn, userID, map[string]any{
+		"source": source,
+		"mfa":    mfa,
+	})
+}
@@ -0,0 +1,299 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang-hexagon/internal/adapter/publisher/memory"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+)
+
+type userEventsExpectedOutput struct {
+	types []domain.EventType
+	data  []map[string]any
+}
+
+func TestUserService_Events(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+
+	existingUser := &domain.User{
+		ID:    userID,
+		Name:  gofakeit.Name(),
+		Email: gofakeit.Email(),
+		Role:  domain.Basic,
+	}
+	registeredUser := &domain.User{
+		ID:    userID,
+		Name:  existingUser.Name,
+		Email: existingUser.Email,
+		Role:  domain.Basic,
+	}
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			hasher *mock.MockPasswordHasher,
+			cache *mock.MockCacheRepository,
+			verification *mock.MockVerificationService,
+		)
+		action   func(userService *service.UserService) error
+		expected userEventsExpectedOutput
+	}{
+		{
+			desc: "Registered",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(gofakeit.UUID(), nil)
+				userRepo.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Return(registeredUser, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Any()).
+					Return(nil)
+				verification.EXPECT().
+					SendVerification(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			action: func(userService *service.UserService) error {
+				_, err := userService.Register(ctx, &domain.User{
+					Name:     existingUser.Name,
+					Email:    existingUser.Email,
+					Password: gofakeit.Password(true, true, true, true, false, 12),
+				})
+				return err
+			},
+			expected: userEventsExpectedOutput{
+				types: []domain.EventType{domain.EventUserRegistered},
+				data: []map[string]any{
+					{"name": existingUser.Name, "email": existingUser.Email, "role": domain.Basic},
+				},
+			},
+		},
+		{
+			desc: "UpdatedWithRoleChange",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(existingUser, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil).
+					Times(2)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			action: func(userService *service.UserService) error {
+				_, err := userService.UpdateUser(ctx, &domain.User{
+					ID:   userID,
+					Name: existingUser.Name + " Jr.",
+					Role: domain.Admin,
+				})
+				return err
+			},
+			expected: userEventsExpectedOutput{
+				types: []domain.EventType{domain.EventUserUpdated, domain.EventUserRoleChanged},
+				data: []map[string]any{
+					{"changed_fields": []string{"name", "role"}},
+					{"old_role": domain.Basic, "new_role": domain.Admin},
+				},
+			},
+		},
+		{
+			desc: "Deleted",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Any()).
+					Return(nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			action: func(userService *service.UserService) error {
+				return userService.DeleteUser(ctx, userID, 0)
+			},
+			expected: userEventsExpectedOutput{
+				types: []domain.EventType{domain.EventUserDeleted},
+				data:  []map[string]any{nil},
+			},
+		},
+		{
+			desc: "Restored",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					RestoreUser(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Any()).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			action: func(userService *service.UserService) error {
+				_, err := userService.RestoreUser(ctx, userID)
+				return err
+			},
+			expected: userEventsExpectedOutput{
+				types: []domain.EventType{domain.EventUserRestored},
+				data:  []map[string]any{nil},
+			},
+		},
+		{
+			desc: "Purged",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					PurgeDeletedUsers(gomock.Any(), gomock.Any()).
+					Return([]uint64{userID}, nil)
+			},
+			action: func(userService *service.UserService) error {
+				_, err := userService.PurgeDeletedUsers(ctx)
+				return err
+			},
+			expected: userEventsExpectedOutput{
+				types: []domain.EventType{domain.EventUserPurged},
+				data:  []map[string]any{nil},
+			},
+		},
+		{
+			desc: "NotPublishedOnFailure",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			action: func(userService *service.UserService) error {
+				err := userService.DeleteUser(ctx, userID, 0)
+				assert.Equal(t, domain.ErrDataNotFound, err, "Error mismatch")
+				return nil
+			},
+			expected: userEventsExpectedOutput{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
+			events := memory.New()
+
+			// the refresh tokens revoked along with the deletion are no events
+			tokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			tokenRepo.EXPECT().
+				RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+				Return(nil).
+				AnyTimes()
+
+			tc.mocks(userRepo, hasher, cache, verification)
+
+			userService := service.NewUserService(userRepo, hasher, cache, verification, mock.NewMockAuthService(ctrl), tokenRepo, events, newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+			err := tc.action(userService)
+			assert.NoError(t, err, "Error mismatch")
+
+			published := events.Events()
+			assert.Len(t, published, len(tc.expected.types), "Events mismatch")
+			for i, event := range published {
+				assert.Equal(t, tc.expected.types[i], event.Type, "Event type mismatch")
+				assert.Equal(t, userID, event.UserID, "Event user mismatch")
+				assert.Equal(t, tc.expected.data[i], event.Data, "Event data mismatch")
+			}
+		})
+	}
+}
+
+func TestUserService_EventPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock.NewMockUserRepository(ctrl)
+	cache := mock.NewMockCacheRepository(ctrl)
+	events := mock.NewMockEventPublisher(ctrl)
+	tokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+
+	userRepo.EXPECT().
+		GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+		Return(&domain.User{ID: userID}, nil)
+	userRepo.EXPECT().
+		DeleteUser(gomock.Any(), gomock.Eq(userID)).
+		Return(nil)
+	tokenRepo.EXPECT().
+		RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+		Return(nil)
+	events.EXPECT().
+		Publish(gomock.Any(), gomock.Any()).
+		Return(domain.ErrInternal)
+
+	userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, mock.NewMockVerificationService(ctrl), mock.NewMockAuthService(ctrl), tokenRepo, events, newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+	// the deletion is rolled back with the event, so the cache is left untouched
+	err := userService.DeleteUser(ctx, userID, 0)
+	assert.Equal(t, domain.ErrInternal, err, "Error mismatch")
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"golang-hexagon/internal/core/util"
+	"time"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes generated for a user
+	recoveryCodeCount = 10
+	// usedTOTPCodeTTL is how long a used TOTP code is remembered to prevent its replay.
+	// It covers the 30 seconds period of the code with one period of clock skew on both sides
+	usedTOTPCodeTTL = 90 * time.Second
+)
+
+// MFAService implements port.MFAService interface
+// and provides access to the user repository, MFA repository,
+// TOTP service, auth service and cache service
+type MFAService struct {
+	repo        port.UserRepository
+	mfaRepo     port.MFARepository
+	totp        port.TOTPService
+	authService port.AuthService
+	cache       port.CacheRepository
+}
+
+// NewMFAService creates a new multi-factor authentication service instance
+func NewMFAService(
+	repo port.UserRepository,
+	mfaRepo port.MFARepository,
+	totp port.TOTPService,
+	authService port.AuthService,
+	cache port.CacheRepository,
+) *MFAService {
+	return &MFAService{
+		repo,
+		mfaRepo,
+		totp,
+		authService,
+		cache,
+	}
+}
+
+// EnrollTOTP creates a pending TOTP authenticator for the user, replacing the previous pending one.
+// The authenticator has to be confirmed with a valid code before it is used on login
+func (ms *MFAService) EnrollTOTP(ctx context.Context, userID uint64) (*domain.TOTPEnrollment, error) {
+	user, err := ms.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	totp, err := ms.mfaRepo.GetTOTPByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrDataNotFound) {
+		return nil, domain.ErrInternal
+	}
+
+	if totp != nil && totp.ConfirmedAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, uri, err := ms.totp.GenerateSecret(user.Email)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	qrCode, err := ms.totp.QRCode(uri)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	_, err = ms.mfaRepo.UpsertTOTP(ctx, &domain.TOTP{
+		UserID: userID,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret: secret,
+		URI:    uri,
+		QRCode: qrCode,
+	}, nil
+}
+
+// ConfirmTOTP enables the pending TOTP authenticator of the user with a valid code
+// and returns new recovery codes. All sessions of the user are revoked,
+// so that the next sessions are authenticated with the second factor
+func (ms *MFAService) ConfirmTOTP(ctx context.Context, userID uint64, code string) ([]string, error) {
+	totp, err := ms.mfaRepo.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrMFANotEnabled
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if totp.ConfirmedAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	err = useTOTPCode(ctx, ms.totp, ms.cache, totp, code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ms.mfaRepo.ConfirmTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrMFAAlreadyEnabled
+		}
+		return nil, domain.ErrInternal
+	}
+
+	codes, err := ms.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ms.authService.RevokeUserTokens(ctx, userID)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP removes the TOTP authenticator and the recovery codes of the user with a valid TOTP or recovery code
+func (ms *MFAService) DisableTOTP(ctx context.Context, userID uint64, code string) error {
+	err := verifyMFACode(ctx, ms.mfaRepo, ms.totp, ms.cache, userID, code)
+	if err != nil {
+		return err
+	}
+
+	err = ms.mfaRepo.DeleteTOTP(ctx, userID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user with a valid TOTP code
+func (ms *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID uint64, code string) ([]string, error) {
+	totp, err := confirmedTOTP(ctx, ms.mfaRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = useTOTPCode(ctx, ms.totp, ms.cache, totp, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms.replaceRecoveryCodes(ctx, userID)
+}
+
+// replaceRecoveryCodes generates new recovery codes and stores their hashes instead of the old ones
+func (ms *MFAService) replaceRecoveryCodes(ctx context.Context, userID uint64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		code, err := util.GenerateRecoveryCode()
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, util.HashRecoveryCode(code))
+	}
+
+	err := ms.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return codes, nil
+}
+
+// confirmedTOTP returns the confirmed TOTP authenticator of the user
+// or domain.ErrMFANotEnabled if the user has none
+func confirmedTOTP(ctx context.Context, mfaRepo port.MFARepository, userID uint64) (*domain.TOTP, error) {
+	totp, err := mfaRepo.GetTOTPByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, domain.ErrMFANotEnabled
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if totp.ConfirmedAt == nil {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	return totp, nil
+}
+
+// verifyMFACode checks the TOTP code or consumes the recovery code of the user with the confirmed TOTP authenticator
+func verifyMFACode(
+	ctx context.Context,
+	mfaRepo port.MFARepository,
+	totpService port.TOTPService,
+	cache port.CacheRepository,
+	userID uint64,
+	code string,
+) error {
+	totp, err := confirmedTOTP(ctx, mfaRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	err = useTOTPCode(ctx, totpService, cache, totp, code)
+	if !errors.Is(err, domain.ErrInvalidMFACode) {
+		return err
+	}
+
+	err = mfaRepo.UseRecoveryCode(ctx, userID, util.HashRecoveryCode(code))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidMFACode
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// useTOTPCode checks the TOTP code and remembers it, so that the same code can not be used twice.
+// The code is counted atomically, only the first use gets the counter to one
+func useTOTPCode(ctx context.Context, totpService port.TOTPService, cache port.CacheRepository, totp *domain.TOTP, code string) error {
+	if !totpService.Validate(code, totp.Secret) {
+		return domain.ErrInvalidMFACode
+	}
+
+	cacheKey := util.GenerateCacheKey("used_totp", fmt.Sprintf("%d-%s", totp.UserID, code))
+
+	uses, err := cache.Increment(ctx, cacheKey, usedTOTPCodeTTL)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	if uses != 1 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,803 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+	"golang-hexagon/internal/core/util"
+	"testing"
+	"time"
+)
+
+type enrollTOTPTestedInput struct {
+	userID uint64
+}
+
+type enrollTOTPExpectedOutput struct {
+	enrollment *domain.TOTPEnrollment
+	err        error
+}
+
+func TestMFAService_EnrollTOTP(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+	}
+	secret := gofakeit.UUID()
+	uri := fmt.Sprintf("otpauth://totp/%s?secret=%s", user.Email, secret)
+	qrCode := []byte(gofakeit.UUID())
+	confirmedAt := time.Now()
+	pendingTOTP := &domain.TOTP{
+		UserID: user.ID,
+		Secret: gofakeit.UUID(),
+	}
+	confirmedTOTP := &domain.TOTP{
+		UserID:      user.ID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	enrollment := &domain.TOTPEnrollment{
+		Secret: secret,
+		URI:    uri,
+		QRCode: qrCode,
+	}
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			mfaRepo *mock.MockMFARepository,
+			totpService *mock.MockTOTPService,
+		)
+		input    enrollTOTPTestedInput
+		expected enrollTOTPExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				totpService.EXPECT().
+					GenerateSecret(gomock.Eq(user.Email)).
+					Return(secret, uri, nil)
+				totpService.EXPECT().
+					QRCode(gomock.Eq(uri)).
+					Return(qrCode, nil)
+				mfaRepo.EXPECT().
+					UpsertTOTP(gomock.Any(), gomock.Eq(&domain.TOTP{
+						UserID: user.ID,
+						Secret: secret,
+					})).
+					DoAndReturn(func(_ context.Context, totp *domain.TOTP) (*domain.TOTP, error) {
+						return totp, nil
+					})
+			},
+			input: enrollTOTPTestedInput{
+				userID: user.ID,
+			},
+			expected: enrollTOTPExpectedOutput{
+				enrollment: enrollment,
+				err:        nil,
+			},
+		},
+		{
+			desc: "Success_ReplacePending",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(pendingTOTP, nil)
+				totpService.EXPECT().
+					GenerateSecret(gomock.Eq(user.Email)).
+					Return(secret, uri, nil)
+				totpService.EXPECT().
+					QRCode(gomock.Eq(uri)).
+					Return(qrCode, nil)
+				mfaRepo.EXPECT().
+					UpsertTOTP(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, totp *domain.TOTP) (*domain.TOTP, error) {
+						return totp, nil
+					})
+			},
+			input: enrollTOTPTestedInput{
+				userID: user.ID,
+			},
+			expected: enrollTOTPExpectedOutput{
+				enrollment: enrollment,
+				err:        nil,
+			},
+		},
+		{
+			desc: "Fail_UserNotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: enrollTOTPTestedInput{
+				userID: user.ID,
+			},
+			expected: enrollTOTPExpectedOutput{
+				enrollment: nil,
+				err:        domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_AlreadyEnabled",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(confirmedTOTP, nil)
+			},
+			input: enrollTOTPTestedInput{
+				userID: user.ID,
+			},
+			expected: enrollTOTPExpectedOutput{
+				enrollment: nil,
+				err:        domain.ErrMFAAlreadyEnabled,
+			},
+		},
+		{
+			desc: "Fail_UpsertTOTP",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+			) {
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(user, nil)
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(user.ID)).
+					Return(nil, domain.ErrDataNotFound)
+				totpService.EXPECT().
+					GenerateSecret(gomock.Eq(user.Email)).
+					Return(secret, uri, nil)
+				totpService.EXPECT().
+					QRCode(gomock.Eq(uri)).
+					Return(qrCode, nil)
+				mfaRepo.EXPECT().
+					UpsertTOTP(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrInternal)
+			},
+			input: enrollTOTPTestedInput{
+				userID: user.ID,
+			},
+			expected: enrollTOTPExpectedOutput{
+				enrollment: nil,
+				err:        domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			totpService := mock.NewMockTOTPService(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, mfaRepo, totpService)
+
+			mfaService := service.NewMFAService(userRepo, mfaRepo, totpService, authService, cache)
+
+			enrollment, err := mfaService.EnrollTOTP(ctx, tc.input.userID)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if !gomock.Eq(tc.expected.enrollment).Matches(enrollment) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.expected.enrollment, enrollment)
+			}
+		})
+	}
+}
+
+type mfaCodeTestedInput struct {
+	userID uint64
+	code   string
+}
+
+type recoveryCodesExpectedOutput struct {
+	codes bool
+	err   error
+}
+
+func TestMFAService_ConfirmTOTP(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	code := gofakeit.DigitN(6)
+	confirmedAt := time.Now()
+	pendingTOTP := &domain.TOTP{
+		UserID: userID,
+		Secret: gofakeit.UUID(),
+	}
+	confirmedTOTP := &domain.TOTP{
+		UserID:      userID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	usedCodeKey := util.GenerateCacheKey("used_totp", fmt.Sprintf("%d-%s", userID, code))
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			mfaRepo *mock.MockMFARepository,
+			totpService *mock.MockTOTPService,
+			authService *mock.MockAuthService,
+			cache *mock.MockCacheRepository,
+		)
+		input    mfaCodeTestedInput
+		expected recoveryCodesExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(pendingTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(pendingTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(1), nil)
+				mfaRepo.EXPECT().
+					ConfirmTOTP(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					ReplaceRecoveryCodes(gomock.Any(), gomock.Eq(userID), gomock.Len(10)).
+					Return(nil)
+				authService.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: true,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Fail_NotEnrolled",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrMFANotEnabled,
+			},
+		},
+		{
+			desc: "Fail_AlreadyEnabled",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrMFAAlreadyEnabled,
+			},
+		},
+		{
+			desc: "Fail_InvalidCode",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(pendingTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(pendingTOTP.Secret)).
+					Return(false)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrInvalidMFACode,
+			},
+		},
+		{
+			desc: "Fail_CodeReused",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(pendingTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(pendingTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(2), nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrInvalidMFACode,
+			},
+		},
+		{
+			desc: "Fail_RevokeTokens",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(pendingTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(pendingTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(1), nil)
+				mfaRepo.EXPECT().
+					ConfirmTOTP(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				mfaRepo.EXPECT().
+					ReplaceRecoveryCodes(gomock.Any(), gomock.Eq(userID), gomock.Len(10)).
+					Return(nil)
+				authService.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(domain.ErrInternal)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			totpService := mock.NewMockTOTPService(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(mfaRepo, totpService, authService, cache)
+
+			mfaService := service.NewMFAService(userRepo, mfaRepo, totpService, authService, cache)
+
+			codes, err := mfaService.ConfirmTOTP(ctx, tc.input.userID, tc.input.code)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if (len(codes) > 0) != tc.expected.codes {
+				t.Errorf("[case: %s] expected to get recovery codes: %t; got %v", tc.desc, tc.expected.codes, codes)
+			}
+		})
+	}
+}
+
+type disableTOTPExpectedOutput struct {
+	err error
+}
+
+func TestMFAService_DisableTOTP(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	code := gofakeit.DigitN(6)
+	confirmedAt := time.Now()
+	pendingTOTP := &domain.TOTP{
+		UserID: userID,
+		Secret: gofakeit.UUID(),
+	}
+	confirmedTOTP := &domain.TOTP{
+		UserID:      userID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	usedCodeKey := util.GenerateCacheKey("used_totp", fmt.Sprintf("%d-%s", userID, code))
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			mfaRepo *mock.MockMFARepository,
+			totpService *mock.MockTOTPService,
+			cache *mock.MockCacheRepository,
+		)
+		input    mfaCodeTestedInput
+		expected disableTOTPExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(1), nil)
+				mfaRepo.EXPECT().
+					DeleteTOTP(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: disableTOTPExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_RecoveryCode",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(userID), gomock.Eq(util.HashRecoveryCode(code))).
+					Return(nil)
+				mfaRepo.EXPECT().
+					DeleteTOTP(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: disableTOTPExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_NotEnabled",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(pendingTOTP, nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: disableTOTPExpectedOutput{
+				err: domain.ErrMFANotEnabled,
+			},
+		},
+		{
+			desc: "Fail_InvalidCode",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(userID), gomock.Eq(util.HashRecoveryCode(code))).
+					Return(domain.ErrDataNotFound)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: disableTOTPExpectedOutput{
+				err: domain.ErrInvalidMFACode,
+			},
+		},
+		{
+			desc: "Fail_DeleteTOTP",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+				mfaRepo.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(userID), gomock.Eq(util.HashRecoveryCode(code))).
+					Return(nil)
+				mfaRepo.EXPECT().
+					DeleteTOTP(gomock.Any(), gomock.Eq(userID)).
+					Return(domain.ErrInternal)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: disableTOTPExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			totpService := mock.NewMockTOTPService(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(mfaRepo, totpService, cache)
+
+			mfaService := service.NewMFAService(userRepo, mfaRepo, totpService, authService, cache)
+
+			err := mfaService.DisableTOTP(ctx, tc.input.userID, tc.input.code)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestMFAService_RegenerateRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	code := gofakeit.DigitN(6)
+	confirmedAt := time.Now()
+	confirmedTOTP := &domain.TOTP{
+		UserID:      userID,
+		Secret:      gofakeit.UUID(),
+		ConfirmedAt: &confirmedAt,
+	}
+	usedCodeKey := util.GenerateCacheKey("used_totp", fmt.Sprintf("%d-%s", userID, code))
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			mfaRepo *mock.MockMFARepository,
+			totpService *mock.MockTOTPService,
+			cache *mock.MockCacheRepository,
+		)
+		input    mfaCodeTestedInput
+		expected recoveryCodesExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(1), nil)
+				mfaRepo.EXPECT().
+					ReplaceRecoveryCodes(gomock.Any(), gomock.Eq(userID), gomock.Len(10)).
+					Return(nil)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: true,
+				err:   nil,
+			},
+		},
+		{
+			desc: "Fail_NotEnabled",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrMFANotEnabled,
+			},
+		},
+		{
+			desc: "Fail_InvalidCode",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(false)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrInvalidMFACode,
+			},
+		},
+		{
+			desc: "Fail_ReplaceRecoveryCodes",
+			mocks: func(
+				mfaRepo *mock.MockMFARepository,
+				totpService *mock.MockTOTPService,
+				cache *mock.MockCacheRepository,
+			) {
+				mfaRepo.EXPECT().
+					GetTOTPByUserID(gomock.Any(), gomock.Eq(userID)).
+					Return(confirmedTOTP, nil)
+				totpService.EXPECT().
+					Validate(gomock.Eq(code), gomock.Eq(confirmedTOTP.Secret)).
+					Return(true)
+				cache.EXPECT().
+					Increment(gomock.Any(), gomock.Eq(usedCodeKey), gomock.Any()).
+					Return(int64(1), nil)
+				mfaRepo.EXPECT().
+					ReplaceRecoveryCodes(gomock.Any(), gomock.Eq(userID), gomock.Len(10)).
+					Return(domain.ErrInternal)
+			},
+			input: mfaCodeTestedInput{
+				userID: userID,
+				code:   code,
+			},
+			expected: recoveryCodesExpectedOutput{
+				codes: false,
+				err:   domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			mfaRepo := mock.NewMockMFARepository(ctrl)
+			totpService := mock.NewMockTOTPService(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(mfaRepo, totpService, cache)
+
+			mfaService := service.NewMFAService(userRepo, mfaRepo, totpService, authService, cache)
+
+			codes, err := mfaService.RegenerateRecoveryCodes(ctx, tc.input.userID, tc.input.code)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if (len(codes) > 0) != tc.expected.codes {
+				t.Errorf("[case: %s] expected to get recovery codes: %t; got %v", tc.desc, tc.expected.codes, codes)
+			}
+		})
+	}
+}
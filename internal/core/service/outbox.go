@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"time"
+)
+
+// OutboxPolicy controls the relay of the outbox.
+// Up to BatchSize events are relayed at a time. A failed event is attempted again after RetryDelay,
+// twice as long after every further failure up to MaxRetryDelay.
+// Delivered events are kept for Retention before they are cleaned up
+type OutboxPolicy struct {
+	BatchSize     uint64
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	Retention     time.Duration
+}
+
+// OutboxService implements port.OutboxService interface
+// and relays the events stored in the outbox through the event publisher.
+// An event may be published more than once if marking it as sent fails,
+// so consumers are expected to deduplicate events by ID
+type OutboxService struct {
+	repo      port.OutboxRepository
+	publisher port.EventPublisher
+	tx        port.Transactor
+	policy    OutboxPolicy
+}
+
+// NewOutboxService creates a new outbox service instance
+func NewOutboxService(repo port.OutboxRepository, publisher port.EventPublisher, tx port.Transactor, policy OutboxPolicy) *OutboxService {
+	return &OutboxService{
+		repo,
+		publisher,
+		tx,
+		policy,
+	}
+}
+
+// RelayPending publishes a batch of pending events in the order they were stored.
+// The batch stays locked until it is relayed, so several relays can run side by side
+func (s *OutboxService) RelayPending(ctx context.Context) (int, error) {
+	var sent int
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		sent = 0
+
+		entries, err := s.repo.ClaimPending(ctx, s.policy.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			err = s.publisher.Publish(ctx, entry.Event)
+			if err != nil {
+				err = s.repo.MarkFailed(ctx, entry.ID, err.Error(), time.Now().Add(s.retryDelay(entry.Attempts)))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			err = s.repo.MarkSent(ctx, entry.ID)
+			if err != nil {
+				return err
+			}
+			sent++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return sent, nil
+}
+
+// retryDelay returns the delay before the next attempt of an event that failed the given number of times before
+func (s *OutboxService) retryDelay(attempts int) time.Duration {
+	delay := s.policy.RetryDelay
+	for i := 0; i < attempts && delay < s.policy.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.policy.MaxRetryDelay)
+}
+
+// Cleanup deletes the events delivered longer ago than the retention
+func (s *OutboxService) Cleanup(ctx context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteSent(ctx, time.Now().Add(-s.policy.Retention))
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,286 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+)
+
+var outboxPolicy = service.OutboxPolicy{
+	BatchSize:     10,
+	RetryDelay:    time.Second,
+	MaxRetryDelay: time.Minute,
+	Retention:     time.Hour,
+}
+
+// fromNow matches a time the offset away from now, allowing for the time the call takes
+type fromNow time.Duration
+
+func (m fromNow) Matches(x any) bool {
+	value, ok := x.(time.Time)
+	if !ok {
+		return false
+	}
+
+	expected := time.Now().Add(time.Duration(m))
+	return !value.After(expected) && value.After(expected.Add(-time.Second))
+}
+
+func (m fromNow) String() string {
+	return fmt.Sprintf("is %s from now", time.Duration(m))
+}
+
+type relayPendingExpectedOutput struct {
+	sent int
+	err  error
+}
+
+func TestOutboxService_RelayPending(t *testing.T) {
+	ctx := context.Background()
+
+	newEntry := func(attempts int) *domain.OutboxEntry {
+		return &domain.OutboxEntry{
+			ID: gofakeit.Uint64(),
+			Event: &domain.Event{
+				ID:         uuid.New(),
+				Type:       domain.EventUserRegistered,
+				UserID:     gofakeit.Uint64(),
+				OccurredAt: time.Now(),
+			},
+			Attempts: attempts,
+		}
+	}
+	first, second := newEntry(0), newEntry(0)
+	failing := newEntry(3)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			outboxRepo *mock.MockOutboxRepository,
+			publisher *mock.MockEventPublisher,
+		)
+		expected relayPendingExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				outboxRepo *mock.MockOutboxRepository,
+				publisher *mock.MockEventPublisher,
+			) {
+				outboxRepo.EXPECT().
+					ClaimPending(gomock.Any(), gomock.Eq(outboxPolicy.BatchSize)).
+					Return([]*domain.OutboxEntry{first, second}, nil)
+				gomock.InOrder(
+					publisher.EXPECT().
+						Publish(gomock.Any(), gomock.Eq(first.Event)).
+						Return(nil),
+					outboxRepo.EXPECT().
+						MarkSent(gomock.Any(), gomock.Eq(first.ID)).
+						Return(nil),
+					publisher.EXPECT().
+						Publish(gomock.Any(), gomock.Eq(second.Event)).
+						Return(nil),
+					outboxRepo.EXPECT().
+						MarkSent(gomock.Any(), gomock.Eq(second.ID)).
+						Return(nil),
+				)
+			},
+			expected: relayPendingExpectedOutput{
+				sent: 2,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Success_NothingPending",
+			mocks: func(
+				outboxRepo *mock.MockOutboxRepository,
+				publisher *mock.MockEventPublisher,
+			) {
+				outboxRepo.EXPECT().
+					ClaimPending(gomock.Any(), gomock.Eq(outboxPolicy.BatchSize)).
+					Return(nil, nil)
+			},
+			expected: relayPendingExpectedOutput{
+				sent: 0,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Success_PublishFailureRetriedWithBackoff",
+			mocks: func(
+				outboxRepo *mock.MockOutboxRepository,
+				publisher *mock.MockEventPublisher,
+			) {
+				outboxRepo.EXPECT().
+					ClaimPending(gomock.Any(), gomock.Eq(outboxPolicy.BatchSize)).
+					Return([]*domain.OutboxEntry{failing, first}, nil)
+				publisher.EXPECT().
+					Publish(gomock.Any(), gomock.Eq(failing.Event)).
+					Return(errors.New("connection refused"))
+				outboxRepo.EXPECT().
+					MarkFailed(gomock.Any(), gomock.Eq(failing.ID), gomock.Eq("connection refused"), fromNow(8*time.Second)).
+					Return(nil)
+				publisher.EXPECT().
+					Publish(gomock.Any(), gomock.Eq(first.Event)).
+					Return(nil)
+				outboxRepo.EXPECT().
+					MarkSent(gomock.Any(), gomock.Eq(first.ID)).
+					Return(nil)
+			},
+			expected: relayPendingExpectedOutput{
+				sent: 1,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Success_RetryDelayCapped",
+			mocks: func(
+				outboxRepo *mock.MockOutboxRepository,
+				publisher *mock.MockEventPublisher,
+			) {
+				entry := newEntry(20)
+				outboxRepo.EXPECT().
+					ClaimPending(gomock.Any(), gomock.Eq(outboxPolicy.BatchSize)).
+					Return([]*domain.OutboxEntry{entry}, nil)
+				publisher.EXPECT().
+					Publish(gomock.Any(), gomock.Eq(entry.Event)).
+					Return(errors.New("connection refused"))
+				outboxRepo.EXPECT().
+					MarkFailed(gomock.Any(), gomock.Eq(entry.ID), gomock.Any(), fromNow(outboxPolicy.MaxRetryDelay)).
+					Return(nil)
+			},
+			expected: relayPendingExpectedOutput{
+				sent: 0,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Fail_ClaimError",
+			mocks: func(
+				outboxRepo *mock.MockOutboxRepository,
+				publisher *mock.MockEventPublisher,
+			) {
+				outboxRepo.EXPECT().
+					ClaimPending(gomock.Any(), gomock.Eq(outboxPolicy.BatchSize)).
+					Return(nil, errors.New("connection refused"))
+			},
+			expected: relayPendingExpectedOutput{
+				sent: 0,
+				err:  domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_MarkSentError",
+			mocks: func(
+				outboxRepo *mock.MockOutboxRepository,
+				publisher *mock.MockEventPublisher,
+			) {
+				outboxRepo.EXPECT().
+					ClaimPending(gomock.Any(), gomock.Eq(outboxPolicy.BatchSize)).
+					Return([]*domain.OutboxEntry{first, second}, nil)
+				publisher.EXPECT().
+					Publish(gomock.Any(), gomock.Eq(first.Event)).
+					Return(nil)
+				outboxRepo.EXPECT().
+					MarkSent(gomock.Any(), gomock.Eq(first.ID)).
+					Return(errors.New("connection refused"))
+			},
+			expected: relayPendingExpectedOutput{
+				sent: 0,
+				err:  domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			outboxRepo := mock.NewMockOutboxRepository(ctrl)
+			publisher := mock.NewMockEventPublisher(ctrl)
+
+			tc.mocks(outboxRepo, publisher)
+
+			outboxService := service.NewOutboxService(outboxRepo, publisher, newTransactor(ctrl), outboxPolicy)
+
+			sent, err := outboxService.RelayPending(ctx)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected.sent, sent, "Sent mismatch")
+		})
+	}
+}
+
+type cleanupExpectedOutput struct {
+	deleted int64
+	err     error
+}
+
+func TestOutboxService_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	deleted := gofakeit.Int64()
+
+	beforeRetention := fromNow(-outboxPolicy.Retention)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(outboxRepo *mock.MockOutboxRepository)
+		expected cleanupExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(outboxRepo *mock.MockOutboxRepository) {
+				outboxRepo.EXPECT().
+					DeleteSent(gomock.Any(), beforeRetention).
+					Return(deleted, nil)
+			},
+			expected: cleanupExpectedOutput{
+				deleted: deleted,
+				err:     nil,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(outboxRepo *mock.MockOutboxRepository) {
+				outboxRepo.EXPECT().
+					DeleteSent(gomock.Any(), beforeRetention).
+					Return(int64(0), errors.New("connection refused"))
+			},
+			expected: cleanupExpectedOutput{
+				deleted: 0,
+				err:     domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			outboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+			tc.mocks(outboxRepo)
+
+			outboxService := service.NewOutboxService(outboxRepo, mock.NewMockEventPublisher(ctrl), mock.NewMockTransactor(ctrl), outboxPolicy)
+
+			deleted, err := outboxService.Cleanup(ctx)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected.deleted, deleted, "Deleted mismatch")
+		})
+	}
+}
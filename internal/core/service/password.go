@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"golang-hexagon/internal/core/util"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordService implements port.PasswordService interface
+// and provides access to the user repository, password hasher, password reset repository,
+// auth service, notifier, cache service, event publisher and transactor
+type PasswordService struct {
+	repo        port.UserRepository
+	hasher      port.PasswordHasher
+	resetRepo   port.PasswordResetRepository
+	authService port.AuthService
+	notifier    port.Notifier
+	cache       port.CacheRepository
+	events      port.EventPublisher
+	tx          port.Transactor
+	resetTTL    time.Duration
+	resetURL    string
+	policy      PasswordPolicy
+}
+
+// NewPasswordService creates a new password service instance.
+// The reset token is appended to the reset URL as the token query parameter, if the URL is given
+func NewPasswordService(
+	repo port.UserRepository,
+	hasher port.PasswordHasher,
+	resetRepo port.PasswordResetRepository,
+	authService port.AuthService,
+	notifier port.Notifier,
+	cache port.CacheRepository,
+	events port.EventPublisher,
+	tx port.Transactor,
+	resetTTL time.Duration,
+	resetURL string,
+	policy PasswordPolicy,
+) *PasswordService {
+	return &PasswordService{
+		repo,
+		hasher,
+		resetRepo,
+		authService,
+		notifier,
+		cache,
+		events,
+		tx,
+		resetTTL,
+		resetURL,
+		policy,
+	}
+}
+
+// ForgotPassword issues a password reset token and sends it to the user.
+// Unknown emails are ignored to not disclose which emails are registered
+func (ps *PasswordService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := ps.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	token, err := util.GenerateToken()
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	_, err = ps.resetRepo.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		ID:        id,
+		UserID:    user.ID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(ps.resetTTL),
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ps.notifier.Notify(ctx, ps.resetNotification(user, token))
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ResetPassword consumes the password reset token, sets the new password
+// and revokes all sessions of the user. The token is consumed, the password is set and the change is announced
+// in one transaction, so a failure leaves the token usable for another attempt
+func (ps *PasswordService) ResetPassword(ctx context.Context, token []byte, password string) error {
+	resetToken, err := ps.resetRepo.GetPasswordResetTokenByHash(ctx, util.HashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidResetToken
+		}
+		return domain.ErrInternal
+	}
+
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return domain.ErrInvalidResetToken
+	}
+
+	user, err := ps.repo.GetUserByID(ctx, resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return domain.ErrInvalidResetToken
+		}
+		return domain.ErrInternal
+	}
+
+	// the token is kept for another attempt if the new password is rejected
+	err = ps.policy.Validate(password, user.Email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := ps.hasher.Hash(password)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = withinTx(ctx, ps.tx, func(ctx context.Context) error {
+		err := ps.resetRepo.UsePasswordResetToken(ctx, resetToken.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return domain.ErrInvalidResetToken
+			}
+			return domain.ErrInternal
+		}
+
+		_, err = ps.repo.UpdateUser(ctx, &domain.User{
+			ID:       resetToken.UserID,
+			Password: hashedPassword,
+		})
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = ps.resetRepo.InvalidateUserPasswordResetTokens(ctx, resetToken.UserID)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = publishEvent(ctx, ps.events, domain.EventUserUpdated, resetToken.UserID, map[string]any{
+			"changed_fields": []string{"password"},
+		})
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = ps.authService.RevokeUserTokens(ctx, resetToken.UserID)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("user", resetToken.UserID)
+
+	err = ps.cache.Delete(ctx, cacheKey)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = ps.cache.DeleteByPrefix(ctx, "users:*")
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// resetNotification composes the password reset notification
+func (ps *PasswordService) resetNotification(user *domain.User, token []byte) *domain.Notification {
+	action := fmt.Sprintf("use the following token to reset your password: %s", token)
+
+	resetURL, err := url.Parse(ps.resetURL)
+	if ps.resetURL != "" && err == nil {
+		query := resetURL.Query()
+		query.Set("token", string(token))
+		resetURL.RawQuery = query.Encode()
+
+		action = fmt.Sprintf("follow the link to reset your password: %s", resetURL)
+	}
+
+	return &domain.Notification{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nWe have received a request to reset your password. Please %s\n\nThe request expires in %s. If you did not request a password reset, you can ignore this message.\n",
+			user.Name,
+			action,
+			ps.resetTTL,
+		),
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"golang-hexagon/internal/core/domain"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is the set of rules every new password has to satisfy.
+// MinLength counts characters, MaxLength counts bytes, since bcrypt ignores everything after the 72nd byte.
+// Denylist contains lowercased common or breached passwords that are rejected regardless of case
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Denylist      map[string]struct{}
+}
+
+// Validate checks the password against the policy and returns *domain.PasswordPolicyError
+// listing every broken rule, or nil if the password satisfies the policy
+func (p PasswordPolicy) Validate(password, email string) error {
+	var violations []domain.PasswordViolation
+
+	addViolation := func(rule domain.PasswordRule, message string) {
+		violations = append(violations, domain.PasswordViolation{
+			Rule:    rule,
+			Message: message,
+		})
+	}
+
+	minLength := max(p.MinLength, 1)
+	if utf8.RuneCountInString(password) < minLength {
+		addViolation(domain.PasswordRuleMinLength, fmt.Sprintf("password must be at least %d characters long", minLength))
+	}
+
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		addViolation(domain.PasswordRuleMaxLength, fmt.Sprintf("password must be at most %d bytes long", p.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		addViolation(domain.PasswordRuleUppercase, "password must contain an uppercase letter")
+	}
+
+	if p.RequireLower && !hasLower {
+		addViolation(domain.PasswordRuleLowercase, "password must contain a lowercase letter")
+	}
+
+	if p.RequireDigit && !hasDigit {
+		addViolation(domain.PasswordRuleDigit, "password must contain a digit")
+	}
+
+	if p.RequireSymbol && !hasSymbol {
+		addViolation(domain.PasswordRuleSymbol, "password must contain a symbol")
+	}
+
+	if _, ok := p.Denylist[strings.ToLower(password)]; ok {
+		addViolation(domain.PasswordRuleDenylist, "password is too common")
+	}
+
+	if email != "" && strings.EqualFold(password, email) {
+		addViolation(domain.PasswordRuleEmail, "password must not be the same as the email")
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{
+			Violations: violations,
+		}
+	}
+
+	return nil
+}
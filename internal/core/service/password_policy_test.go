@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"errors"
+	"github.com/brianvoe/gofakeit/v6"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/service"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type validatePasswordTestedInput struct {
+	password string
+	email    string
+}
+
+type validatePasswordExpectedOutput struct {
+	rules []domain.PasswordRule
+}
+
+var passwordPolicy = service.PasswordPolicy{
+	MinLength: 8,
+	MaxLength: 72,
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	email := gofakeit.Email()
+	policy := service.PasswordPolicy{
+		MinLength:     8,
+		MaxLength:     72,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		Denylist: map[string]struct{}{
+			"p@ssw0rd!": {},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		input    validatePasswordTestedInput
+		expected validatePasswordExpectedOutput
+	}{
+		{
+			desc: "Success",
+			input: validatePasswordTestedInput{
+				password: "Str0ng-Passw0rd",
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: nil,
+			},
+		},
+		{
+			desc: "Success_MultibyteCharacters",
+			input: validatePasswordTestedInput{
+				password: "Пароль-123",
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: nil,
+			},
+		},
+		{
+			desc: "Fail_Empty",
+			input: validatePasswordTestedInput{
+				password: "",
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: []domain.PasswordRule{
+					domain.PasswordRuleMinLength,
+					domain.PasswordRuleUppercase,
+					domain.PasswordRuleLowercase,
+					domain.PasswordRuleDigit,
+					domain.PasswordRuleSymbol,
+				},
+			},
+		},
+		{
+			desc: "Fail_TooShort",
+			input: validatePasswordTestedInput{
+				password: "Sh0rt-",
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: []domain.PasswordRule{
+					domain.PasswordRuleMinLength,
+				},
+			},
+		},
+		{
+			desc: "Fail_TooLong",
+			input: validatePasswordTestedInput{
+				password: "L0ng-" + strings.Repeat("я", 36),
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: []domain.PasswordRule{
+					domain.PasswordRuleMaxLength,
+				},
+			},
+		},
+		{
+			desc: "Fail_MissingClasses",
+			input: validatePasswordTestedInput{
+				password: "lowercaseonly",
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: []domain.PasswordRule{
+					domain.PasswordRuleUppercase,
+					domain.PasswordRuleDigit,
+					domain.PasswordRuleSymbol,
+				},
+			},
+		},
+		{
+			desc: "Fail_Denylisted",
+			input: validatePasswordTestedInput{
+				password: "P@ssw0rd!",
+				email:    email,
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: []domain.PasswordRule{
+					domain.PasswordRuleDenylist,
+				},
+			},
+		},
+		{
+			desc: "Fail_SameAsEmail",
+			input: validatePasswordTestedInput{
+				password: "Us3r@Example.com",
+				email:    "us3r@example.com",
+			},
+			expected: validatePasswordExpectedOutput{
+				rules: []domain.PasswordRule{
+					domain.PasswordRuleEmail,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := policy.Validate(tc.input.password, tc.input.email)
+
+			var rules []domain.PasswordRule
+			var policyErr *domain.PasswordPolicyError
+			if errors.As(err, &policyErr) {
+				if !errors.Is(err, domain.ErrWeakPassword) {
+					t.Errorf("[case: %s] expected the error to match %q", tc.desc, domain.ErrWeakPassword)
+				}
+				for _, violation := range policyErr.Violations {
+					rules = append(rules, violation.Rule)
+				}
+			} else if err != nil {
+				t.Errorf("[case: %s] expected to get a password policy error; got %q", tc.desc, err)
+			}
+
+			if !reflect.DeepEqual(rules, tc.expected.rules) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.expected.rules, rules)
+			}
+		})
+	}
+}
@@ -0,0 +1,570 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"golang-hexagon/internal/adapter/publisher/memory"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+	"golang-hexagon/internal/core/util"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const resetURL = "https://example.com/reset-password"
+
+type forgotPasswordTestedInput struct {
+	email string
+}
+
+type forgotPasswordExpectedOutput struct {
+	err error
+}
+
+func TestPasswordService_ForgotPassword(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Name:  gofakeit.Name(),
+		Email: gofakeit.Email(),
+	}
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			resetRepo *mock.MockPasswordResetRepository,
+			notifier *mock.MockNotifier,
+		)
+		input    forgotPasswordTestedInput
+		expected forgotPasswordExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				resetRepo *mock.MockPasswordResetRepository,
+				notifier *mock.MockNotifier,
+			) {
+				var tokenHash string
+
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(user, nil)
+				resetRepo.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+						if token.UserID != user.ID {
+							t.Errorf("expected the reset token to belong to user %d; got %d", user.ID, token.UserID)
+						}
+						tokenHash = token.TokenHash
+						return token, nil
+					})
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, notification *domain.Notification) error {
+						if notification.To != user.Email {
+							t.Errorf("expected the notification to be sent to %s; got %s", user.Email, notification.To)
+						}
+						i := strings.Index(notification.Body, "?token=")
+						if i < 0 {
+							t.Fatalf("expected the notification to contain the reset link; got %q", notification.Body)
+						}
+						token := strings.Fields(notification.Body[i+len("?token="):])[0]
+						if util.HashToken([]byte(token)) != tokenHash {
+							t.Errorf("expected the notified token to match the stored hash")
+						}
+						return nil
+					})
+			},
+			input: forgotPasswordTestedInput{
+				email: user.Email,
+			},
+			expected: forgotPasswordExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_UnknownEmail",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				resetRepo *mock.MockPasswordResetRepository,
+				notifier *mock.MockNotifier,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: forgotPasswordTestedInput{
+				email: user.Email,
+			},
+			expected: forgotPasswordExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				resetRepo *mock.MockPasswordResetRepository,
+				notifier *mock.MockNotifier,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: forgotPasswordTestedInput{
+				email: user.Email,
+			},
+			expected: forgotPasswordExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_CreateToken",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				resetRepo *mock.MockPasswordResetRepository,
+				notifier *mock.MockNotifier,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(user, nil)
+				resetRepo.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrInternal)
+			},
+			input: forgotPasswordTestedInput{
+				email: user.Email,
+			},
+			expected: forgotPasswordExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_Notify",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				resetRepo *mock.MockPasswordResetRepository,
+				notifier *mock.MockNotifier,
+			) {
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(user, nil)
+				resetRepo.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+						return token, nil
+					})
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Return(errors.New("smtp error"))
+			},
+			input: forgotPasswordTestedInput{
+				email: user.Email,
+			},
+			expected: forgotPasswordExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			resetRepo := mock.NewMockPasswordResetRepository(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+			notifier := mock.NewMockNotifier(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, resetRepo, notifier)
+
+			passwordService := service.NewPasswordService(userRepo, mock.NewMockPasswordHasher(ctrl), resetRepo, authService, notifier, cache, memory.New(), newTransactor(ctrl), time.Hour, resetURL, passwordPolicy)
+
+			err := passwordService.ForgotPassword(ctx, tc.input.email)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
+
+type resetPasswordTestedInput struct {
+	token    []byte
+	password string
+}
+
+type resetPasswordExpectedOutput struct {
+	published bool
+	err       error
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	token, _ := util.GenerateToken()
+	hash := util.HashToken(token)
+	password := gofakeit.Password(true, true, true, true, false, 8)
+	hashedPassword := gofakeit.UUID()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+	}
+	usedAt := time.Now().Add(-time.Minute)
+
+	activeToken := &domain.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    gofakeit.Uint64(),
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	usedToken := &domain.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    activeToken.UserID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+	expiredToken := &domain.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    activeToken.UserID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}
+	cacheKey := util.GenerateCacheKey("user", activeToken.UserID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			hasher *mock.MockPasswordHasher,
+			resetRepo *mock.MockPasswordResetRepository,
+			authService *mock.MockAuthService,
+			cache *mock.MockCacheRepository,
+		)
+		input    resetPasswordTestedInput
+		expected resetPasswordExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(activeToken, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(user, nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(hashedPassword, nil)
+				resetRepo.EXPECT().
+					UsePasswordResetToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Return(nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
+						if user.ID != activeToken.UserID {
+							t.Errorf("expected to update user %d; got %d", activeToken.UserID, user.ID)
+						}
+						if user.Password != hashedPassword {
+							t.Errorf("expected the password to be updated with the hash of the new password")
+						}
+						return user, nil
+					})
+				resetRepo.EXPECT().
+					InvalidateUserPasswordResetTokens(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(nil)
+				authService.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				published: true,
+				err:       nil,
+			},
+		},
+		{
+			desc: "Fail_TokenNotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrInvalidResetToken,
+			},
+		},
+		{
+			desc: "Fail_TokenUsed",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(usedToken, nil)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrInvalidResetToken,
+			},
+		},
+		{
+			desc: "Fail_TokenExpired",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(expiredToken, nil)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrInvalidResetToken,
+			},
+		},
+		{
+			desc: "Fail_TokenUsedConcurrently",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(activeToken, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(user, nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(hashedPassword, nil)
+				resetRepo.EXPECT().
+					UsePasswordResetToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Return(domain.ErrDataNotFound)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrInvalidResetToken,
+			},
+		},
+		{
+			desc: "Fail_WeakPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(activeToken, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(user, nil)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: "short",
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrWeakPassword,
+			},
+		},
+		{
+			desc: "Fail_HashPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(activeToken, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(user, nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return("", errors.New("hash error"))
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_UpdateUser",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(activeToken, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(user, nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(hashedPassword, nil)
+				resetRepo.EXPECT().
+					UsePasswordResetToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Return(nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrInternal)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_RevokeTokens",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				resetRepo *mock.MockPasswordResetRepository,
+				authService *mock.MockAuthService,
+				cache *mock.MockCacheRepository,
+			) {
+				resetRepo.EXPECT().
+					GetPasswordResetTokenByHash(gomock.Any(), gomock.Eq(hash)).
+					Return(activeToken, nil)
+				userRepo.EXPECT().
+					GetUserByID(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(user, nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(password)).
+					Return(hashedPassword, nil)
+				resetRepo.EXPECT().
+					UsePasswordResetToken(gomock.Any(), gomock.Eq(activeToken.ID)).
+					Return(nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
+						return user, nil
+					})
+				resetRepo.EXPECT().
+					InvalidateUserPasswordResetTokens(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(nil)
+				authService.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Eq(activeToken.UserID)).
+					Return(domain.ErrInternal)
+			},
+			input: resetPasswordTestedInput{
+				token:    token,
+				password: password,
+			},
+			expected: resetPasswordExpectedOutput{
+				published: true,
+				err:       domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
+			resetRepo := mock.NewMockPasswordResetRepository(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+			notifier := mock.NewMockNotifier(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, hasher, resetRepo, authService, cache)
+
+			events := memory.New()
+
+			passwordService := service.NewPasswordService(userRepo, hasher, resetRepo, authService, notifier, cache, events, newTransactor(ctrl), time.Hour, resetURL, passwordPolicy)
+
+			err := passwordService.ResetPassword(ctx, tc.input.token, tc.input.password)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			published := events.Events()
+			if tc.expected.published && (len(published) != 1 || published[0].Type != domain.EventUserUpdated) {
+				t.Errorf("[case: %s] expected to publish a %q event; got %v", tc.desc, domain.EventUserUpdated, published)
+			}
+			if tc.expected.published && len(published) == 1 && !reflect.DeepEqual(published[0].Data, map[string]any{"changed_fields": []string{"password"}}) {
+				t.Errorf("[case: %s] expected the %q field to change; got %v", tc.desc, "password", published[0].Data)
+			}
+			if !tc.expected.published && len(published) != 0 {
+				t.Errorf("[case: %s] expected to publish no events; got %v", tc.desc, published)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+)
+
+// RoleService implements port.RoleService interface
+// and provides access to the role repository and cache service
+type RoleService struct {
+	repo  port.RoleRepository
+	cache port.CacheRepository
+}
+
+// NewRoleService creates a new role service instance
+func NewRoleService(repo port.RoleRepository, cache port.CacheRepository) *RoleService {
+	return &RoleService{
+		repo,
+		cache,
+	}
+}
+
+// CreateRole creates a new role with the given permissions
+func (rs *RoleService) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	role, err := rs.repo.CreateRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflictingData) || errors.Is(err, domain.ErrUnknownPermission) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return role, nil
+}
+
+// GetRole gets a role by ID
+func (rs *RoleService) GetRole(ctx context.Context, id uint64) (*domain.Role, error) {
+	role, err := rs.repo.GetRoleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return role, nil
+}
+
+// ListRoles lists roles with pagination
+func (rs *RoleService) ListRoles(ctx context.Context, skip, limit uint64) ([]*domain.Role, error) {
+	roles, err := rs.repo.ListRoles(ctx, skip, limit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return roles, nil
+}
+
+// UpdateRole updates the description of a role and replaces its permissions if they are given.
+// The name of a role can not be changed, since it is stored in the issued access tokens.
+// The permissions of built-in roles can not be changed, so that the admin role always holds the permissions to manage roles
+func (rs *RoleService) UpdateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	existingRole, err := rs.repo.GetRoleByID(ctx, role.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	emptyData := role.Description == "" && role.Permissions == nil
+	if emptyData {
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	if role.Permissions != nil && existingRole.BuiltIn() {
+		return nil, domain.ErrBuiltInRole
+	}
+
+	role, err = rs.repo.UpdateRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownPermission) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	err = rs.cache.Delete(ctx, roleCacheKey(existingRole.Name))
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return role, nil
+}
+
+// DeleteRole deletes a role by ID. Built-in roles and roles assigned to users can not be deleted
+func (rs *RoleService) DeleteRole(ctx context.Context, id uint64) error {
+	role, err := rs.repo.GetRoleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	if role.BuiltIn() {
+		return domain.ErrBuiltInRole
+	}
+
+	err = rs.repo.DeleteRole(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrRoleInUse) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	err = rs.cache.Delete(ctx, roleCacheKey(role.Name))
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ListPermissions lists all permissions that can be granted to roles
+func (rs *RoleService) ListPermissions(ctx context.Context) ([]domain.Permission, error) {
+	permissions, err := rs.repo.ListPermissions(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return permissions, nil
+}
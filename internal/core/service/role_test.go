@@ -0,0 +1,457 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+	"golang-hexagon/internal/core/util"
+	"reflect"
+	"testing"
+)
+
+type createRoleTestedInput struct {
+	role *domain.Role
+}
+
+type roleExpectedOutput struct {
+	role *domain.Role
+	err  error
+}
+
+func TestRoleService_CreateRole(t *testing.T) {
+	ctx := context.Background()
+	roleInput := &domain.Role{
+		Name:        domain.UserRole("support"),
+		Description: gofakeit.Sentence(3),
+		Permissions: []domain.Permission{
+			{Resource: domain.ResourceUsers, Action: domain.ActionRead},
+		},
+	}
+	roleOutput := &domain.Role{
+		ID:          gofakeit.Uint64(),
+		Name:        roleInput.Name,
+		Description: roleInput.Description,
+		Permissions: []domain.Permission{
+			{ID: gofakeit.Uint64(), Resource: domain.ResourceUsers, Action: domain.ActionRead},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		mocks    func(roleRepo *mock.MockRoleRepository)
+		input    createRoleTestedInput
+		expected roleExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(roleRepo *mock.MockRoleRepository) {
+				roleRepo.EXPECT().
+					CreateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(roleOutput, nil)
+			},
+			input: createRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: roleOutput,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Fail_DuplicateName",
+			mocks: func(roleRepo *mock.MockRoleRepository) {
+				roleRepo.EXPECT().
+					CreateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(nil, domain.ErrConflictingData)
+			},
+			input: createRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrConflictingData,
+			},
+		},
+		{
+			desc: "Fail_UnknownPermission",
+			mocks: func(roleRepo *mock.MockRoleRepository) {
+				roleRepo.EXPECT().
+					CreateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(nil, domain.ErrUnknownPermission)
+			},
+			input: createRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrUnknownPermission,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(roleRepo *mock.MockRoleRepository) {
+				roleRepo.EXPECT().
+					CreateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(nil, errors.New("connection refused"))
+			},
+			input: createRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roleRepo := mock.NewMockRoleRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(roleRepo)
+
+			roleService := service.NewRoleService(roleRepo, cache)
+
+			role, err := roleService.CreateRole(ctx, tc.input.role)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if !reflect.DeepEqual(role, tc.expected.role) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.expected.role, role)
+			}
+		})
+	}
+}
+
+type updateRoleTestedInput struct {
+	role *domain.Role
+}
+
+func TestRoleService_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+	existingRole := &domain.Role{
+		ID:          gofakeit.Uint64(),
+		Name:        domain.UserRole("support"),
+		Description: gofakeit.Sentence(3),
+	}
+	roleInput := &domain.Role{
+		ID: existingRole.ID,
+		Permissions: []domain.Permission{
+			{Resource: domain.ResourceUsers, Action: domain.ActionUnlock},
+		},
+	}
+	roleOutput := &domain.Role{
+		ID:          existingRole.ID,
+		Name:        existingRole.Name,
+		Description: existingRole.Description,
+		Permissions: []domain.Permission{
+			{ID: gofakeit.Uint64(), Resource: domain.ResourceUsers, Action: domain.ActionUnlock},
+		},
+	}
+	cacheKey := util.GenerateCacheKey("role", existingRole.Name)
+	builtInRole := &domain.Role{
+		ID:          gofakeit.Uint64(),
+		Name:        domain.Admin,
+		Description: gofakeit.Sentence(3),
+	}
+	builtInDescriptionInput := &domain.Role{
+		ID:          builtInRole.ID,
+		Description: gofakeit.Sentence(3),
+	}
+	builtInDescriptionOutput := &domain.Role{
+		ID:          builtInRole.ID,
+		Name:        builtInRole.Name,
+		Description: builtInDescriptionInput.Description,
+	}
+	builtInCacheKey := util.GenerateCacheKey("role", builtInRole.Name)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository)
+		input    updateRoleTestedInput
+		expected roleExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(existingRole.ID)).
+					Return(existingRole, nil)
+				roleRepo.EXPECT().
+					UpdateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(roleOutput, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+			},
+			input: updateRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: roleOutput,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Success_BuiltInDescription",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(builtInRole.ID)).
+					Return(builtInRole, nil)
+				roleRepo.EXPECT().
+					UpdateRole(gomock.Any(), gomock.Eq(builtInDescriptionInput)).
+					Return(builtInDescriptionOutput, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(builtInCacheKey)).
+					Return(nil)
+			},
+			input: updateRoleTestedInput{
+				role: builtInDescriptionInput,
+			},
+			expected: roleExpectedOutput{
+				role: builtInDescriptionOutput,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Fail_BuiltInPermissions",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(builtInRole.ID)).
+					Return(builtInRole, nil)
+			},
+			input: updateRoleTestedInput{
+				role: &domain.Role{
+					ID:          builtInRole.ID,
+					Permissions: []domain.Permission{},
+				},
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrBuiltInRole,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(existingRole.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: updateRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_EmptyData",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(existingRole.ID)).
+					Return(existingRole, nil)
+			},
+			input: updateRoleTestedInput{
+				role: &domain.Role{
+					ID: existingRole.ID,
+				},
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrNoUpdatedData,
+			},
+		},
+		{
+			desc: "Fail_UnknownPermission",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(existingRole.ID)).
+					Return(existingRole, nil)
+				roleRepo.EXPECT().
+					UpdateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(nil, domain.ErrUnknownPermission)
+			},
+			input: updateRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrUnknownPermission,
+			},
+		},
+		{
+			desc: "Fail_DeleteCache",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(existingRole.ID)).
+					Return(existingRole, nil)
+				roleRepo.EXPECT().
+					UpdateRole(gomock.Any(), gomock.Eq(roleInput)).
+					Return(roleOutput, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(domain.ErrInternal)
+			},
+			input: updateRoleTestedInput{
+				role: roleInput,
+			},
+			expected: roleExpectedOutput{
+				role: nil,
+				err:  domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roleRepo := mock.NewMockRoleRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(roleRepo, cache)
+
+			roleService := service.NewRoleService(roleRepo, cache)
+
+			role, err := roleService.UpdateRole(ctx, tc.input.role)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			if !reflect.DeepEqual(role, tc.expected.role) {
+				t.Errorf("[case: %s] expected to get %v; got %v", tc.desc, tc.expected.role, role)
+			}
+		})
+	}
+}
+
+type deleteRoleTestedInput struct {
+	id uint64
+}
+
+type deleteRoleExpectedOutput struct {
+	err error
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	ctx := context.Background()
+	role := &domain.Role{
+		ID:   gofakeit.Uint64(),
+		Name: domain.UserRole("support"),
+	}
+	builtInRole := &domain.Role{
+		ID:   gofakeit.Uint64(),
+		Name: domain.Admin,
+	}
+	cacheKey := util.GenerateCacheKey("role", role.Name)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository)
+		input    deleteRoleTestedInput
+		expected deleteRoleExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(role.ID)).
+					Return(role, nil)
+				roleRepo.EXPECT().
+					DeleteRole(gomock.Any(), gomock.Eq(role.ID)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+			},
+			input: deleteRoleTestedInput{
+				id: role.ID,
+			},
+			expected: deleteRoleExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(role.ID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: deleteRoleTestedInput{
+				id: role.ID,
+			},
+			expected: deleteRoleExpectedOutput{
+				err: domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_BuiltInRole",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(builtInRole.ID)).
+					Return(builtInRole, nil)
+			},
+			input: deleteRoleTestedInput{
+				id: builtInRole.ID,
+			},
+			expected: deleteRoleExpectedOutput{
+				err: domain.ErrBuiltInRole,
+			},
+		},
+		{
+			desc: "Fail_RoleInUse",
+			mocks: func(roleRepo *mock.MockRoleRepository, cache *mock.MockCacheRepository) {
+				roleRepo.EXPECT().
+					GetRoleByID(gomock.Any(), gomock.Eq(role.ID)).
+					Return(role, nil)
+				roleRepo.EXPECT().
+					DeleteRole(gomock.Any(), gomock.Eq(role.ID)).
+					Return(domain.ErrRoleInUse)
+			},
+			input: deleteRoleTestedInput{
+				id: role.ID,
+			},
+			expected: deleteRoleExpectedOutput{
+				err: domain.ErrRoleInUse,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roleRepo := mock.NewMockRoleRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(roleRepo, cache)
+
+			roleService := service.NewRoleService(roleRepo, cache)
+
+			err := roleService.DeleteRole(ctx, tc.input.id)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+)
+
+// withinTx runs fn in a transaction of the transactor and returns the error of fn.
+// fn reports domain errors, so any other error is a failure of the transaction itself and is reported as internal
+func withinTx(ctx context.Context, tx port.Transactor, fn func(ctx context.Context) error) error {
+	var fnErr error
+
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		fnErr = fn(ctx)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	"golang-hexagon/internal/core/port/mock"
+)
+
+// newTransactor returns a transactor that runs the unit of work without a transaction
+func newTransactor(ctrl *gomock.Controller) *mock.MockTransactor {
+	tx := mock.NewMockTransactor(ctrl)
+	tx.EXPECT().
+		WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	return tx
+}
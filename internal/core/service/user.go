@@ -6,31 +6,80 @@ import (
 	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port"
 	"golang-hexagon/internal/core/util"
+	"time"
 )
 
+// UserService implements port.UserService interface.
+// Deleted users are kept for the retention, until then they can be restored
 type UserService struct {
-	repo  port.UserRepository
-	cache port.CacheRepository
+	repo         port.UserRepository
+	hasher       port.PasswordHasher
+	cache        port.CacheRepository
+	verification port.VerificationService
+	authService  port.AuthService
+	tokenRepo    port.RefreshTokenRepository
+	events       port.EventPublisher
+	tx           port.Transactor
+	accessTTL    time.Duration
+	retention    time.Duration
+	policy       PasswordPolicy
 }
 
 // NewUserService creates a new user service instance
-func NewUserService(repo port.UserRepository, cache port.CacheRepository) *UserService {
+func NewUserService(
+	repo port.UserRepository,
+	hasher port.PasswordHasher,
+	cache port.CacheRepository,
+	verification port.VerificationService,
+	authService port.AuthService,
+	tokenRepo port.RefreshTokenRepository,
+	events port.EventPublisher,
+	tx port.Transactor,
+	accessTTL time.Duration,
+	retention time.Duration,
+	policy PasswordPolicy,
+) *UserService {
 	return &UserService{
-		repo:  repo,
-		cache: cache,
+		repo:         repo,
+		hasher:       hasher,
+		cache:        cache,
+		verification: verification,
+		authService:  authService,
+		tokenRepo:    tokenRepo,
+		events:       events,
+		tx:           tx,
+		accessTTL:    accessTTL,
+		retention:    retention,
+		policy:       policy,
 	}
 }
 
-// Register creates a new user
+// Register creates a new user and sends the email verification token
 func (s *UserService) Register(ctx context.Context, user *domain.User) (*domain.User, error) {
-	hashedPassword, err := util.HashPassword(user.Password)
+	err := s.policy.Validate(user.Password, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.hasher.Hash(user.Password)
 	if err != nil {
 		return nil, domain.ErrInternal
 	}
 
 	user.Password = hashedPassword
 
-	user, err = s.repo.CreateUser(ctx, user)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err = s.repo.CreateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		return publishEvent(ctx, s.events, domain.EventUserRegistered, user.ID, map[string]any{
+			"name":  user.Name,
+			"email": user.Email,
+			"role":  user.Role,
+		})
+	})
 	if err != nil {
 		if errors.Is(err, domain.ErrConflictingData) {
 			return nil, err
@@ -54,6 +103,10 @@ func (s *UserService) Register(ctx context.Context, user *domain.User) (*domain.
 		return nil, domain.ErrInternal
 	}
 
+	// The user is already registered, so a failed delivery is not an error,
+	// the verification email can be requested again
+	_ = s.verification.SendVerification(ctx, user)
+
 	return user, nil
 }
 
@@ -126,16 +179,109 @@ func (s *UserService) ListUsers(ctx context.Context, skip, limit uint64) ([]*dom
 	return users, nil
 }
 
-// UpdateUser updates a user's name, email, and password
+// UpdateUser updates a user's name, email, password, and role.
+// The user is locked while it is updated, so concurrent updates are applied one after the other,
+// and an update based on an outdated version of the user is rejected
 func (s *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
-	existingUser, err := s.repo.GetUserByID(ctx, user.ID)
+	var roleChanged, emailChanged bool
+
+	err := withinTx(ctx, s.tx, func(ctx context.Context) error {
+		existingUser, err := s.repo.GetUserByIDForUpdate(ctx, user.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		emailChanged = user.Email != "" && user.Email != existingUser.Email
+
+		roleChanged, err = s.updateUser(ctx, existingUser, user)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, domain.ErrDataNotFound) {
-			return nil, err
+		return nil, err
+	}
+
+	err = s.refreshUserCache(ctx, user, roleChanged)
+	if err != nil {
+		return nil, err
+	}
+
+	// The update cleared the verification of the previous email. The email is changed already,
+	// so a failed delivery is not an error, the verification email can be requested again
+	if emailChanged {
+		_ = s.verification.SendVerification(ctx, user)
+	}
+
+	return user, nil
+}
+
+// UpdateProfile updates the name, email, and password of the user on their own behalf.
+// The role can not be changed, and the current password is required to change the email or the password.
+// Changing the password signs the user out of every session but the one of the access token
+func (s *UserService) UpdateProfile(ctx context.Context, payload *domain.TokenPayload, user *domain.User, currentPassword string) (*domain.User, error) {
+	if user.Role != "" {
+		return nil, domain.ErrSelfRoleChange
+	}
+
+	var emailChanged bool
+	passwordChanged := user.Password != ""
+
+	err := withinTx(ctx, s.tx, func(ctx context.Context) error {
+		existingUser, err := s.repo.GetUserByIDForUpdate(ctx, user.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return err
+			}
+			return domain.ErrInternal
 		}
-		return nil, domain.ErrInternal
+
+		emailChanged = user.Email != "" && user.Email != existingUser.Email
+		if emailChanged || user.Password != "" {
+			if currentPassword == "" {
+				return domain.ErrInvalidCurrentPassword
+			}
+
+			err = s.hasher.Compare(currentPassword, existingUser.Password)
+			if err != nil {
+				return domain.ErrInvalidCurrentPassword
+			}
+		}
+
+		_, err = s.updateUser(ctx, existingUser, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	err = s.refreshUserCache(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if passwordChanged {
+		err = s.authService.RevokeOtherSessions(ctx, payload)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+	}
+
+	// The update cleared the verification of the previous email. The email is changed already,
+	// so a failed delivery is not an error, the verification email can be requested again
+	if emailChanged {
+		_ = s.verification.SendVerification(ctx, user)
+	}
+
+	return user, nil
+}
+
+// updateUser applies the changes of the user to the existing user and announces them
+// within the ambient transaction. It reports whether the role of the user changed
+func (s *UserService) updateUser(ctx context.Context, existingUser, user *domain.User) (bool, error) {
+	var err error
+
 	emptyData := user.Name == "" &&
 		user.Email == "" &&
 		user.Password == "" &&
@@ -144,15 +290,31 @@ func (s *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domai
 		existingUser.Email == user.Email &&
 		existingUser.Role == user.Role
 	if emptyData || sameData {
-		return nil, domain.ErrNoUpdatedData
+		return false, domain.ErrNoUpdatedData
+	}
+
+	if user.Version != 0 && user.Version != existingUser.Version {
+		return false, domain.ErrVersionMismatch
 	}
 
+	changedFields := changedUserFields(existingUser, user)
+
 	var hashedPassword string
 
 	if user.Password != "" {
-		hashedPassword, err = util.HashPassword(user.Password)
+		email := user.Email
+		if email == "" {
+			email = existingUser.Email
+		}
+
+		err = s.policy.Validate(user.Password, email)
 		if err != nil {
-			return nil, domain.ErrInternal
+			return false, err
+		}
+
+		hashedPassword, err = s.hasher.Hash(user.Password)
+		if err != nil {
+			return false, domain.ErrInternal
 		}
 	}
 
@@ -160,45 +322,126 @@ func (s *UserService) UpdateUser(ctx context.Context, user *domain.User) (*domai
 
 	_, err = s.repo.UpdateUser(ctx, user)
 	if err != nil {
-		if err == domain.ErrConflictingData {
-			return nil, err
+		if err == domain.ErrConflictingData || err == domain.ErrUnknownRole || err == domain.ErrVersionMismatch {
+			return false, err
+		}
+		return false, domain.ErrInternal
+	}
+
+	if len(changedFields) > 0 {
+		err = publishEvent(ctx, s.events, domain.EventUserUpdated, user.ID, map[string]any{
+			"changed_fields": changedFields,
+		})
+		if err != nil {
+			return false, domain.ErrInternal
+		}
+	}
+
+	roleChanged := user.Role != "" && user.Role != existingUser.Role
+	if roleChanged {
+		err = publishEvent(ctx, s.events, domain.EventUserRoleChanged, user.ID, map[string]any{
+			"old_role": existingUser.Role,
+			"new_role": user.Role,
+		})
+		if err != nil {
+			return false, domain.ErrInternal
+		}
+	}
+
+	return roleChanged, nil
+}
+
+// refreshUserCache caches the updated user once the update is committed,
+// and revokes the access tokens of the user if the role changed
+func (s *UserService) refreshUserCache(ctx context.Context, user *domain.User, roleChanged bool) error {
+	if roleChanged {
+		err := revokeUserAccessTokens(ctx, s.cache, user.ID, "", s.accessTTL)
+		if err != nil {
+			return domain.ErrInternal
 		}
-		return nil, domain.ErrInternal
 	}
 
 	cacheKey := util.GenerateCacheKey("user", user.ID)
 
-	err = s.cache.Delete(ctx, cacheKey)
+	err := s.cache.Delete(ctx, cacheKey)
 	if err != nil {
-		return nil, domain.ErrInternal
+		return domain.ErrInternal
 	}
 
 	userSerialized, err := util.Serialize(user)
 	if err != nil {
-		return nil, domain.ErrInternal
+		return domain.ErrInternal
 	}
 
 	err = s.cache.Set(ctx, cacheKey, userSerialized, 0)
 	if err != nil {
-		return nil, domain.ErrInternal
+		return domain.ErrInternal
 	}
 
 	err = s.cache.DeleteByPrefix(ctx, "users:*")
 	if err != nil {
-		return nil, domain.ErrInternal
+		return domain.ErrInternal
 	}
 
-	return user, nil
+	return nil
 }
 
-// DeleteUser deletes a user by ID
-func (s *UserService) DeleteUser(ctx context.Context, id uint64) error {
-	_, err := s.repo.GetUserByID(ctx, id)
-	if err != nil {
-		if err == domain.ErrDataNotFound {
-			return err
+// changedUserFields returns the names of the fields the update changes
+func changedUserFields(existingUser, user *domain.User) []string {
+	var fields []string
+
+	if user.Name != "" && user.Name != existingUser.Name {
+		fields = append(fields, "name")
+	}
+	if user.Email != "" && user.Email != existingUser.Email {
+		fields = append(fields, "email")
+	}
+	if user.Password != "" {
+		fields = append(fields, "password")
+	}
+	if user.Role != "" && user.Role != existingUser.Role {
+		fields = append(fields, "role")
+	}
+
+	return fields
+}
+
+// DeleteUser deletes a user by ID, the user is kept until it is purged and can be restored until then.
+// A non-zero version has to be the current version of the user, which is locked until it is deleted.
+// All access and refresh tokens of the user are revoked
+func (s *UserService) DeleteUser(ctx context.Context, id, version uint64) error {
+	err := withinTx(ctx, s.tx, func(ctx context.Context) error {
+		existingUser, err := s.repo.GetUserByIDForUpdate(ctx, id)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return err
+			}
+			return domain.ErrInternal
 		}
-		return domain.ErrInternal
+
+		if version != 0 && version != existingUser.Version {
+			return domain.ErrVersionMismatch
+		}
+
+		err = s.repo.DeleteUser(ctx, id)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = s.tokenRepo.RevokeUserRefreshTokens(ctx, id)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = publishEvent(ctx, s.events, domain.EventUserDeleted, id, nil)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	cacheKey := util.GenerateCacheKey("user", id)
@@ -213,5 +456,102 @@ func (s *UserService) DeleteUser(ctx context.Context, id uint64) error {
 		return domain.ErrInternal
 	}
 
-	return s.repo.DeleteUser(ctx, id)
+	err = revokeUserAccessTokens(ctx, s.cache, id, "", s.accessTTL)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ListDeletedUsers lists the deleted users that are not purged yet
+func (s *UserService) ListDeletedUsers(ctx context.Context, skip, limit uint64) ([]*domain.User, error) {
+	users, err := s.repo.ListDeletedUsers(ctx, skip, limit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return users, nil
+}
+
+// RestoreUser restores a deleted user by ID.
+// The access and refresh tokens revoked by the deletion stay revoked, so the user has to log in again
+func (s *UserService) RestoreUser(ctx context.Context, id uint64) (*domain.User, error) {
+	var user *domain.User
+
+	err := withinTx(ctx, s.tx, func(ctx context.Context) error {
+		var err error
+
+		user, err = s.repo.RestoreUser(ctx, id)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		err = publishEvent(ctx, s.events, domain.EventUserRestored, id, nil)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.refreshUserCache(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// PurgeUser permanently deletes a deleted user by ID, after which its email can be registered again
+func (s *UserService) PurgeUser(ctx context.Context, id uint64) error {
+	return withinTx(ctx, s.tx, func(ctx context.Context) error {
+		err := s.repo.PurgeUser(ctx, id)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return err
+			}
+			return domain.ErrInternal
+		}
+
+		err = publishEvent(ctx, s.events, domain.EventUserPurged, id, nil)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		return nil
+	})
+}
+
+// PurgeDeletedUsers permanently deletes the users deleted longer ago than the retention
+func (s *UserService) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	var purged int
+
+	err := withinTx(ctx, s.tx, func(ctx context.Context) error {
+		ids, err := s.repo.PurgeDeletedUsers(ctx, time.Now().Add(-s.retention))
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		for _, id := range ids {
+			err = publishEvent(ctx, s.events, domain.EventUserPurged, id, nil)
+			if err != nil {
+				return domain.ErrInternal
+			}
+		}
+
+		purged = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
 }
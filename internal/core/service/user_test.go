@@ -2,9 +2,12 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"golang-hexagon/internal/adapter/publisher/memory"
 	"golang-hexagon/internal/core/domain"
 	"golang-hexagon/internal/core/port/mock"
 	"golang-hexagon/internal/core/service"
@@ -13,6 +16,13 @@ import (
 	"time"
 )
 
+const (
+	// accessTTL is the lifetime of the access tokens
+	accessTTL = 15 * time.Minute
+	// deletedRetention is how long deleted users are kept before they are purged
+	deletedRetention = 30 * 24 * time.Hour
+)
+
 type registerTestedInput struct {
 	user *domain.User
 }
@@ -27,7 +37,7 @@ func TestUserService_Register(t *testing.T) {
 	userName := gofakeit.Name()
 	userEmail := gofakeit.Email()
 	userPassword := gofakeit.Password(true, true, true, true, false, 8)
-	hashedPassword, _ := util.HashPassword(userPassword)
+	hashedPassword := gofakeit.UUID()
 
 	userInput := &domain.User{
 		Name:     userName,
@@ -52,7 +62,9 @@ func TestUserService_Register(t *testing.T) {
 		desc  string
 		mocks func(
 			userRepo *mock.MockUserRepository,
+			hasher *mock.MockPasswordHasher,
 			cache *mock.MockCacheRepository,
+			verification *mock.MockVerificationService,
 		)
 		input    registerTestedInput
 		expected registerExpectedOutput
@@ -61,8 +73,45 @@ func TestUserService_Register(t *testing.T) {
 			desc: "Success",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
+				userRepo.EXPECT().
+					CreateUser(gomock.Any(), gomock.Eq(userInput)).
+					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq(userSerialized), gomock.Eq(ttl)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				verification.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(userOutput)).
+					Return(nil)
+			},
+			input: registerTestedInput{
+				user: userInput,
+			},
+			expected: registerExpectedOutput{
+				user: userOutput,
+				err:  nil,
+			},
+		},
+		{
+			desc: "Success_VerificationNotSent",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -72,6 +121,9 @@ func TestUserService_Register(t *testing.T) {
 				cache.EXPECT().
 					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
 					Return(nil)
+				verification.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(userOutput)).
+					Return(domain.ErrInternal)
 			},
 			input: registerTestedInput{
 				user: userInput,
@@ -81,12 +133,65 @@ func TestUserService_Register(t *testing.T) {
 				err:  nil,
 			},
 		},
+		{
+			desc: "Fail_WeakPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+			},
+			input: registerTestedInput{
+				user: &domain.User{
+					Name:     userName,
+					Email:    userEmail,
+					Password: userEmail,
+				},
+			},
+			expected: registerExpectedOutput{
+				user: nil,
+				err: &domain.PasswordPolicyError{
+					Violations: []domain.PasswordViolation{
+						{
+							Rule:    domain.PasswordRuleEmail,
+							Message: "password must not be the same as the email",
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Fail_HashPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return("", errors.New("hash error"))
+			},
+			input: registerTestedInput{
+				user: userInput,
+			},
+			expected: registerExpectedOutput{
+				user: nil,
+				err:  domain.ErrInternal,
+			},
+		},
 		{
 			desc: "Fail_InternalError",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(nil, domain.ErrInternal)
@@ -103,8 +208,13 @@ func TestUserService_Register(t *testing.T) {
 			desc: "Fail_DuplicateData",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(nil, domain.ErrConflictingData)
@@ -121,8 +231,13 @@ func TestUserService_Register(t *testing.T) {
 			desc: "Fail_SetCache",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -142,8 +257,13 @@ func TestUserService_Register(t *testing.T) {
 			desc: "Fail_DeleteCacheByPrefix",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
+				hasher.EXPECT().
+					Hash(gomock.Any()).
+					Return(hashedPassword, nil)
 				userRepo.EXPECT().
 					CreateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
@@ -173,11 +293,13 @@ func TestUserService_Register(t *testing.T) {
 			defer ctrl.Finish()
 
 			userRepo := mock.NewMockUserRepository(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
 
-			tc.mocks(userRepo, cache)
+			tc.mocks(userRepo, hasher, cache, verification)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, hasher, cache, verification, mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
 
 			user, err := userService.Register(ctx, tc.input.user)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -356,10 +478,11 @@ func TestUserService_GetUser(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
 
 			tc.mocks(userRepo, cache)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, verification, mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
 
 			user, err := userService.GetUser(ctx, tc.input.id)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -382,8 +505,7 @@ func TestUserService_ListUsers(t *testing.T) {
 	var users []*domain.User
 
 	for i := 0; i < 10; i++ {
-		userPassword := gofakeit.Password(true, true, true, true, false, 8)
-		hashedPassword, _ := util.HashPassword(userPassword)
+		hashedPassword := gofakeit.UUID()
 
 		users = append(users, &domain.User{
 			ID:       gofakeit.Uint64(),
@@ -533,10 +655,11 @@ func TestUserService_ListUsers(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
 
 			tc.mocks(userRepo, cache)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, verification, mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
 
 			users, err := userService.ListUsers(ctx, tc.input.skip, tc.input.limit)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -580,6 +703,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 	}
 
 	cacheKey := util.GenerateCacheKey("user", userID)
+	revokedCacheKey := util.GenerateCacheKey("revoked_user", userID)
 	userSerialized, _ := util.Serialize(userOutput)
 	ttl := time.Duration(0)
 
@@ -588,6 +712,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 		mocks func(
 			userRepo *mock.MockUserRepository,
 			cache *mock.MockCacheRepository,
+			verification *mock.MockVerificationService,
 		)
 		input    updateUserTestedInput
 		expected updateUserExpectedOutput
@@ -597,13 +722,17 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
@@ -613,6 +742,9 @@ func TestUserService_UpdateUser(t *testing.T) {
 				cache.EXPECT().
 					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
 					Return(nil)
+				verification.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(userOutput)).
+					Return(nil)
 			},
 			input: updateUserTestedInput{
 				user: userInput,
@@ -622,14 +754,93 @@ func TestUserService_UpdateUser(t *testing.T) {
 				err:  nil,
 			},
 		},
+		{
+			desc: "Fail_WeakPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+			},
+			input: updateUserTestedInput{
+				user: &domain.User{
+					ID:       userID,
+					Password: "short",
+				},
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err: &domain.PasswordPolicyError{
+					Violations: []domain.PasswordViolation{
+						{
+							Rule:    domain.PasswordRuleMinLength,
+							Message: "password must be at least 8 characters long",
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Fail_VersionMismatch",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID, Name: existingUser.Name, Version: 3}, nil)
+			},
+			input: updateUserTestedInput{
+				user: &domain.User{
+					ID:      userID,
+					Name:    gofakeit.Name(),
+					Version: 2,
+				},
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrVersionMismatch,
+			},
+		},
+		{
+			desc: "Fail_VersionMismatchOnWrite",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID, Name: existingUser.Name, Version: 3}, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrVersionMismatch)
+			},
+			input: updateUserTestedInput{
+				user: &domain.User{
+					ID:      userID,
+					Name:    gofakeit.Name(),
+					Version: 3,
+				},
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrVersionMismatch,
+			},
+		},
 		{
 			desc: "Fail_NotFound",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(nil, domain.ErrDataNotFound)
 			},
 			input: updateUserTestedInput{
@@ -645,9 +856,10 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(nil, domain.ErrInternal)
 			},
 			input: updateUserTestedInput{
@@ -663,9 +875,10 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 			},
 			input: updateUserTestedInput{
@@ -683,9 +896,10 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 			},
 			input: updateUserTestedInput{
@@ -701,9 +915,10 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
@@ -717,14 +932,37 @@ func TestUserService_UpdateUser(t *testing.T) {
 				err:  domain.ErrConflictingData,
 			},
 		},
+		{
+			desc: "Fail_UnknownRole",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
+					Return(nil, domain.ErrUnknownRole)
+			},
+			input: updateUserTestedInput{
+				user: userInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrUnknownRole,
+			},
+		},
 		{
 			desc: "Fail_InternalErrorUpdate",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
@@ -738,18 +976,47 @@ func TestUserService_UpdateUser(t *testing.T) {
 				err:  domain.ErrInternal,
 			},
 		},
+		{
+			desc: "Fail_RevokeTokens",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
+					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(domain.ErrInternal)
+			},
+			input: updateUserTestedInput{
+				user: userInput,
+			},
+			expected: updateUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrInternal,
+			},
+		},
 		{
 			desc: "Fail_DeleteCache",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(domain.ErrInternal)
@@ -767,13 +1034,17 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
@@ -794,13 +1065,17 @@ func TestUserService_UpdateUser(t *testing.T) {
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
 					Return(existingUser, nil)
 				userRepo.EXPECT().
 					UpdateUser(gomock.Any(), gomock.Eq(userInput)).
 					Return(userOutput, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
@@ -831,10 +1106,11 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 			userRepo := mock.NewMockUserRepository(ctrl)
 			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
 
-			tc.mocks(userRepo, cache)
+			tc.mocks(userRepo, cache, verification)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, verification, mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
 
 			user, err := userService.UpdateUser(ctx, tc.input.user)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
@@ -843,159 +1119,751 @@ func TestUserService_UpdateUser(t *testing.T) {
 	}
 }
 
-type deleteUserTestedInput struct {
-	id uint64
+type updateProfileTestedInput struct {
+	user            *domain.User
+	currentPassword string
 }
 
-type deleteUserExpectedOutput struct {
-	err error
+type updateProfileExpectedOutput struct {
+	user *domain.User
+	err  error
 }
 
-func TestUserService_DeleteUser(t *testing.T) {
+func TestUserService_UpdateProfile(t *testing.T) {
 	ctx := context.Background()
 	userID := gofakeit.Uint64()
+	currentPassword := gofakeit.Password(true, true, true, true, false, 12)
+	newPassword := "N3w-" + gofakeit.Password(true, true, true, false, false, 12)
+	hashedPassword := gofakeit.UUID()
+
+	existingUser := &domain.User{
+		ID:       userID,
+		Name:     gofakeit.Name(),
+		Email:    gofakeit.Email(),
+		Password: gofakeit.UUID(),
+		Role:     domain.Basic,
+	}
+	nameInput := &domain.User{
+		ID:   userID,
+		Name: gofakeit.Name(),
+	}
+	emailInput := &domain.User{
+		ID:    userID,
+		Email: gofakeit.Email(),
+	}
+	passwordInput := &domain.User{
+		ID:       userID,
+		Password: newPassword,
+	}
+
+	payload := &domain.TokenPayload{
+		ID:     uuid.New(),
+		UserID: userID,
+		Claims: map[string]any{domain.SessionClaim: uuid.NewString()},
+	}
 
 	cacheKey := util.GenerateCacheKey("user", userID)
+	ttl := time.Duration(0)
 
 	testCases := []struct {
 		desc  string
 		mocks func(
 			userRepo *mock.MockUserRepository,
+			hasher *mock.MockPasswordHasher,
 			cache *mock.MockCacheRepository,
+			verification *mock.MockVerificationService,
+			authService *mock.MockAuthService,
 		)
-		input    deleteUserTestedInput
-		expected deleteUserExpectedOutput
+		input    func() updateProfileTestedInput
+		expected updateProfileExpectedOutput
 	}{
 		{
-			desc: "Success",
+			desc: "Success_NameOnly",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
-					Return(&domain.User{}, nil)
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(nameInput, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
 				cache.EXPECT().
-					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
 					Return(nil)
-				userRepo.EXPECT().
-					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
 					Return(nil)
 			},
-			input: deleteUserTestedInput{
-				id: userID,
+			input: func() updateProfileTestedInput {
+				user := *nameInput
+				return updateProfileTestedInput{user: &user}
 			},
-			expected: deleteUserExpectedOutput{
-				err: nil,
+			expected: updateProfileExpectedOutput{
+				user: nameInput,
+				err:  nil,
 			},
 		},
 		{
-			desc: "Fail_NotFound",
+			desc: "Success_EmailChange",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
-					Return(nil, domain.ErrDataNotFound)
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(currentPassword), gomock.Eq(existingUser.Password)).
+					Return(nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(emailInput)).
+					Return(emailInput, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				verification.EXPECT().
+					SendVerification(gomock.Any(), gomock.Eq(emailInput)).
+					Return(errors.New("smtp error"))
 			},
-			input: deleteUserTestedInput{
-				id: userID,
+			input: func() updateProfileTestedInput {
+				user := *emailInput
+				return updateProfileTestedInput{user: &user, currentPassword: currentPassword}
 			},
-			expected: deleteUserExpectedOutput{
-				err: domain.ErrDataNotFound,
+			expected: updateProfileExpectedOutput{
+				user: emailInput,
+				err:  nil,
 			},
 		},
 		{
-			desc: "Fail_InternalErrorGetByID",
+			desc: "Success_PasswordChange",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
-					Return(nil, domain.ErrInternal)
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(currentPassword), gomock.Eq(existingUser.Password)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(newPassword)).
+					Return(hashedPassword, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(&domain.User{ID: userID, Password: hashedPassword})).
+					Return(&domain.User{ID: userID, Password: hashedPassword}, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				authService.EXPECT().
+					RevokeOtherSessions(gomock.Any(), gomock.Eq(payload)).
+					Return(nil)
 			},
-			input: deleteUserTestedInput{
-				id: userID,
+			input: func() updateProfileTestedInput {
+				user := *passwordInput
+				return updateProfileTestedInput{user: &user, currentPassword: currentPassword}
 			},
-			expected: deleteUserExpectedOutput{
-				err: domain.ErrInternal,
+			expected: updateProfileExpectedOutput{
+				user: &domain.User{ID: userID, Password: hashedPassword},
+				err:  nil,
 			},
 		},
 		{
-			desc: "Fail_DeleteCache",
+			desc: "Fail_RevokeOtherSessions",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
 				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
-					Return(&domain.User{}, nil)
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(currentPassword), gomock.Eq(existingUser.Password)).
+					Return(nil)
+				hasher.EXPECT().
+					Hash(gomock.Eq(newPassword)).
+					Return(hashedPassword, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Eq(&domain.User{ID: userID, Password: hashedPassword})).
+					Return(&domain.User{ID: userID, Password: hashedPassword}, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Any(), gomock.Eq(ttl)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				authService.EXPECT().
+					RevokeOtherSessions(gomock.Any(), gomock.Eq(payload)).
 					Return(domain.ErrInternal)
 			},
-			input: deleteUserTestedInput{
-				id: userID,
+			input: func() updateProfileTestedInput {
+				user := *passwordInput
+				return updateProfileTestedInput{user: &user, currentPassword: currentPassword}
+			},
+			expected: updateProfileExpectedOutput{
+				user: nil,
+				err:  domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_SelfRoleChange",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
+			) {
+			},
+			input: func() updateProfileTestedInput {
+				return updateProfileTestedInput{
+					user:            &domain.User{ID: userID, Role: domain.Admin},
+					currentPassword: currentPassword,
+				}
+			},
+			expected: updateProfileExpectedOutput{
+				user: nil,
+				err:  domain.ErrSelfRoleChange,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: func() updateProfileTestedInput {
+				user := *nameInput
+				return updateProfileTestedInput{user: &user}
+			},
+			expected: updateProfileExpectedOutput{
+				user: nil,
+				err:  domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_MissingCurrentPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+			},
+			input: func() updateProfileTestedInput {
+				user := *emailInput
+				return updateProfileTestedInput{user: &user}
+			},
+			expected: updateProfileExpectedOutput{
+				user: nil,
+				err:  domain.ErrInvalidCurrentPassword,
+			},
+		},
+		{
+			desc: "Fail_InvalidCurrentPassword",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				hasher *mock.MockPasswordHasher,
+				cache *mock.MockCacheRepository,
+				verification *mock.MockVerificationService,
+				authService *mock.MockAuthService,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				hasher.EXPECT().
+					Compare(gomock.Eq(currentPassword), gomock.Eq(existingUser.Password)).
+					Return(domain.ErrInvalidCredentials)
+			},
+			input: func() updateProfileTestedInput {
+				user := *emailInput
+				return updateProfileTestedInput{user: &user, currentPassword: currentPassword}
+			},
+			expected: updateProfileExpectedOutput{
+				user: nil,
+				err:  domain.ErrInvalidCurrentPassword,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			hasher := mock.NewMockPasswordHasher(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
+			authService := mock.NewMockAuthService(ctrl)
+
+			tc.mocks(userRepo, hasher, cache, verification, authService)
+
+			userService := service.NewUserService(userRepo, hasher, cache, verification, authService, mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+			input := tc.input()
+			user, err := userService.UpdateProfile(ctx, payload, input.user, input.currentPassword)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected.user, user, "User mismatch")
+		})
+	}
+}
+
+type deleteUserTestedInput struct {
+	id      uint64
+	version uint64
+}
+
+type deleteUserExpectedOutput struct {
+	err error
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+
+	cacheKey := util.GenerateCacheKey("user", userID)
+	revokedCacheKey := util.GenerateCacheKey("revoked_user", userID)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			cache *mock.MockCacheRepository,
+			tokenRepo *mock.MockRefreshTokenRepository,
+		)
+		input    deleteUserTestedInput
+		expected deleteUserExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{}, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(nil)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_VersionMatch",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID, Version: 3}, nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(nil)
+			},
+			input: deleteUserTestedInput{
+				id:      userID,
+				version: 3,
+			},
+			expected: deleteUserExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_VersionMismatch",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID, Version: 3}, nil)
+			},
+			input: deleteUserTestedInput{
+				id:      userID,
+				version: 2,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrVersionMismatch,
+			},
+		},
+		{
+			desc: "Fail_RevokeTokens",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{}, nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(revokedCacheKey), gomock.Any(), gomock.Eq(accessTTL)).
+					Return(domain.ErrInternal)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrDataNotFound,
+			},
+		},
+		{
+			desc: "Fail_InternalErrorGetByID",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_DeleteCache",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{}, nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(domain.ErrInternal)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_DeleteCacheByPrefix",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{}, nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(cacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(domain.ErrInternal)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_RevokeRefreshTokens",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{}, nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(errors.New("connection refused"))
+			},
+			input: deleteUserTestedInput{
+				id: userID,
+			},
+			expected: deleteUserExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_InternalErrorDelete",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+				tokenRepo *mock.MockRefreshTokenRepository,
+			) {
+				user := &domain.User{
+					ID: userID,
+				}
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(user, nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(domain.ErrInternal)
+			},
+			input: deleteUserTestedInput{
+				id: userID,
 			},
 			expected: deleteUserExpectedOutput{
 				err: domain.ErrInternal,
 			},
 		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+			verification := mock.NewMockVerificationService(ctrl)
+			tokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+
+			tc.mocks(userRepo, cache, tokenRepo)
+
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, verification, mock.NewMockAuthService(ctrl), tokenRepo, memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+			err := userService.DeleteUser(ctx, tc.input.id, tc.input.version)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+		})
+	}
+}
+
+type restoreUserExpectedOutput struct {
+	user *domain.User
+	err  error
+}
+
+func TestUserService_RestoreUser(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+
+	restoredUser := &domain.User{
+		ID:      userID,
+		Name:    gofakeit.Name(),
+		Email:   gofakeit.Email(),
+		Role:    domain.Basic,
+		Version: 3,
+	}
+
+	cacheKey := util.GenerateCacheKey("user", userID)
+	userSerialized, _ := util.Serialize(restoredUser)
+	ttl := time.Duration(0)
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			cache *mock.MockCacheRepository,
+		)
+		expected restoreUserExpectedOutput
+	}{
 		{
-			desc: "Fail_DeleteCacheByPrefix",
+			desc: "Success",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
 			) {
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
-					Return(&domain.User{}, nil)
+					RestoreUser(gomock.Any(), gomock.Eq(userID)).
+					Return(restoredUser, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq(userSerialized), gomock.Eq(ttl)).
+					Return(nil)
 				cache.EXPECT().
 					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
-					Return(domain.ErrInternal)
+					Return(nil)
 			},
-			input: deleteUserTestedInput{
-				id: userID,
+			expected: restoreUserExpectedOutput{
+				user: restoredUser,
+				err:  nil,
 			},
-			expected: deleteUserExpectedOutput{
-				err: domain.ErrInternal,
+		},
+		{
+			desc: "Fail_NotFound",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					RestoreUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			expected: restoreUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrDataNotFound,
 			},
 		},
 		{
-			desc: "Fail_InternalErrorDelete",
+			desc: "Fail_InternalError",
 			mocks: func(
 				userRepo *mock.MockUserRepository,
 				cache *mock.MockCacheRepository,
 			) {
-				user := &domain.User{
-					ID: userID,
-				}
 				userRepo.EXPECT().
-					GetUserByID(gomock.Any(), gomock.Eq(userID)).
-					Return(user, nil)
+					RestoreUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, errors.New("connection refused"))
+			},
+			expected: restoreUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_SetCache",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				cache *mock.MockCacheRepository,
+			) {
+				userRepo.EXPECT().
+					RestoreUser(gomock.Any(), gomock.Eq(userID)).
+					Return(restoredUser, nil)
 				cache.EXPECT().
 					Delete(gomock.Any(), gomock.Eq(cacheKey)).
 					Return(nil)
 				cache.EXPECT().
-					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
-					Return(nil)
-				userRepo.EXPECT().
-					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Set(gomock.Any(), gomock.Eq(cacheKey), gomock.Eq(userSerialized), gomock.Eq(ttl)).
 					Return(domain.ErrInternal)
 			},
-			input: deleteUserTestedInput{
-				id: userID,
-			},
-			expected: deleteUserExpectedOutput{
-				err: domain.ErrInternal,
+			expected: restoreUserExpectedOutput{
+				user: nil,
+				err:  domain.ErrInternal,
 			},
 		},
 	}
@@ -1012,10 +1880,312 @@ func TestUserService_DeleteUser(t *testing.T) {
 
 			tc.mocks(userRepo, cache)
 
-			userService := service.NewUserService(userRepo, cache)
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, mock.NewMockVerificationService(ctrl), mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+			user, err := userService.RestoreUser(ctx, userID)
+			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected.user, user, "User mismatch")
+		})
+	}
+}
+
+func TestUserService_RefreshAfterDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	user := &domain.User{
+		ID:    userID,
+		Name:  gofakeit.Name(),
+		Email: gofakeit.Email(),
+		Role:  domain.Basic,
+	}
+
+	refreshToken := []byte(gofakeit.UUID())
+	storedToken := &domain.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    userID,
+		TokenHash: util.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mock.NewMockUserRepository(ctrl)
+	cache := mock.NewMockCacheRepository(ctrl)
+	tokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+
+	userRepo.EXPECT().
+		GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+		Return(user, nil)
+	userRepo.EXPECT().
+		DeleteUser(gomock.Any(), gomock.Eq(userID)).
+		Return(nil)
+	userRepo.EXPECT().
+		RestoreUser(gomock.Any(), gomock.Eq(userID)).
+		Return(user, nil)
+	tokenRepo.EXPECT().
+		RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+		DoAndReturn(func(ctx context.Context, userID uint64) error {
+			revokedAt := time.Now()
+			storedToken.RevokedAt = &revokedAt
+			return nil
+		})
+	tokenRepo.EXPECT().
+		GetRefreshTokenByHash(gomock.Any(), gomock.Eq(storedToken.TokenHash)).
+		DoAndReturn(func(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+			token := *storedToken
+			return &token, nil
+		}).
+		Times(2)
+	cache.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	cache.EXPECT().
+		DeleteByPrefix(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	cache.EXPECT().
+		Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	authService := service.NewAuthService(userRepo, mock.NewMockPasswordHasher(ctrl), mock.NewMockTokenService(ctrl), tokenRepo, mock.NewMockMFARepository(ctrl), mock.NewMockTOTPService(ctrl), cache, memory.New(), newTransactor(ctrl), accessTTL, time.Hour, loginPolicy)
+	userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), cache, mock.NewMockVerificationService(ctrl), authService, tokenRepo, memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
 
-			err := userService.DeleteUser(ctx, tc.input.id)
+	err := userService.DeleteUser(ctx, userID, 0)
+	assert.NoError(t, err, "Error mismatch")
+
+	_, err = authService.Refresh(ctx, refreshToken)
+	assert.Equal(t, domain.ErrInvalidRefreshToken, err, "Refresh after delete mismatch")
+
+	_, err = userService.RestoreUser(ctx, userID)
+	assert.NoError(t, err, "Error mismatch")
+
+	// the refresh tokens revoked by the deletion stay revoked
+	_, err = authService.Refresh(ctx, refreshToken)
+	assert.Equal(t, domain.ErrInvalidRefreshToken, err, "Refresh after restore mismatch")
+}
+
+func TestUserService_PurgeUser(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+
+	testCases := []struct {
+		desc     string
+		mocks    func(userRepo *mock.MockUserRepository)
+		expected error
+	}{
+		{
+			desc: "Success",
+			mocks: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().
+					PurgeUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			expected: nil,
+		},
+		{
+			desc: "Fail_NotDeleted",
+			mocks: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().
+					PurgeUser(gomock.Any(), gomock.Eq(userID)).
+					Return(domain.ErrDataNotFound)
+			},
+			expected: domain.ErrDataNotFound,
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().
+					PurgeUser(gomock.Any(), gomock.Eq(userID)).
+					Return(errors.New("connection refused"))
+			},
+			expected: domain.ErrInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+
+			tc.mocks(userRepo)
+
+			// the cache has no expectations, deleted users are not cached
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), mock.NewMockCacheRepository(ctrl), mock.NewMockVerificationService(ctrl), mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+			err := userService.PurgeUser(ctx, userID)
+			assert.Equal(t, tc.expected, err, "Error mismatch")
+		})
+	}
+}
+
+type purgeDeletedUsersExpectedOutput struct {
+	purged int
+	err    error
+}
+
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	ctx := context.Background()
+
+	beforeRetention := fromNow(-deletedRetention)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(userRepo *mock.MockUserRepository)
+		expected purgeDeletedUsersExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().
+					PurgeDeletedUsers(gomock.Any(), beforeRetention).
+					Return([]uint64{gofakeit.Uint64(), gofakeit.Uint64()}, nil)
+			},
+			expected: purgeDeletedUsersExpectedOutput{
+				purged: 2,
+				err:    nil,
+			},
+		},
+		{
+			desc: "Success_NothingToPurge",
+			mocks: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().
+					PurgeDeletedUsers(gomock.Any(), beforeRetention).
+					Return(nil, nil)
+			},
+			expected: purgeDeletedUsersExpectedOutput{
+				purged: 0,
+				err:    nil,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(userRepo *mock.MockUserRepository) {
+				userRepo.EXPECT().
+					PurgeDeletedUsers(gomock.Any(), beforeRetention).
+					Return(nil, errors.New("connection refused"))
+			},
+			expected: purgeDeletedUsersExpectedOutput{
+				purged: 0,
+				err:    domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+
+			tc.mocks(userRepo)
+
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), mock.NewMockCacheRepository(ctrl), mock.NewMockVerificationService(ctrl), mock.NewMockAuthService(ctrl), mock.NewMockRefreshTokenRepository(ctrl), memory.New(), newTransactor(ctrl), accessTTL, deletedRetention, passwordPolicy)
+
+			purged, err := userService.PurgeDeletedUsers(ctx)
 			assert.Equal(t, tc.expected.err, err, "Error mismatch")
+			assert.Equal(t, tc.expected.purged, purged, "Purged mismatch")
+		})
+	}
+}
+
+func TestUserService_CommitFailure(t *testing.T) {
+	ctx := context.Background()
+	userID := gofakeit.Uint64()
+	existingUser := &domain.User{
+		ID:   userID,
+		Name: gofakeit.Name(),
+		Role: domain.Basic,
+	}
+
+	testCases := []struct {
+		desc   string
+		mocks  func(userRepo *mock.MockUserRepository, tokenRepo *mock.MockRefreshTokenRepository)
+		action func(userService *service.UserService) error
+	}{
+		{
+			desc: "UpdateUser",
+			mocks: func(userRepo *mock.MockUserRepository, tokenRepo *mock.MockRefreshTokenRepository) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(existingUser, nil)
+			},
+			action: func(userService *service.UserService) error {
+				_, err := userService.UpdateUser(ctx, &domain.User{ID: userID, Name: gofakeit.Name()})
+				return err
+			},
+		},
+		{
+			desc: "DeleteUser",
+			mocks: func(userRepo *mock.MockUserRepository, tokenRepo *mock.MockRefreshTokenRepository) {
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+				userRepo.EXPECT().
+					DeleteUser(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+				tokenRepo.EXPECT().
+					RevokeUserRefreshTokens(gomock.Any(), gomock.Eq(userID)).
+					Return(nil)
+			},
+			action: func(userService *service.UserService) error {
+				return userService.DeleteUser(ctx, userID, 0)
+			},
+		},
+		{
+			desc: "RestoreUser",
+			mocks: func(userRepo *mock.MockUserRepository, tokenRepo *mock.MockRefreshTokenRepository) {
+				userRepo.EXPECT().
+					RestoreUser(gomock.Any(), gomock.Eq(userID)).
+					Return(existingUser, nil)
+			},
+			action: func(userService *service.UserService) error {
+				_, err := userService.RestoreUser(ctx, userID)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			tx := mock.NewMockTransactor(ctrl)
+			tx.EXPECT().
+				WithinTx(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					if err := fn(ctx); err != nil {
+						return err
+					}
+					return errors.New("commit failed")
+				})
+
+			tokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+
+			tc.mocks(userRepo, tokenRepo)
+
+			// the cache has no expectations, it is left untouched when the change is not committed
+			userService := service.NewUserService(userRepo, mock.NewMockPasswordHasher(ctrl), mock.NewMockCacheRepository(ctrl), mock.NewMockVerificationService(ctrl), mock.NewMockAuthService(ctrl), tokenRepo, memory.New(), tx, accessTTL, deletedRetention, passwordPolicy)
+
+			err := tc.action(userService)
+			assert.Equal(t, domain.ErrInternal, err, "Error mismatch")
 		})
 	}
 }
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port"
+	"golang-hexagon/internal/core/util"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VerificationService implements port.VerificationService interface
+// and provides access to the user repository, notifier, cache service, event publisher and transactor
+type VerificationService struct {
+	repo            port.UserRepository
+	notifier        port.Notifier
+	cache           port.CacheRepository
+	events          port.EventPublisher
+	tx              port.Transactor
+	verificationTTL time.Duration
+	resendInterval  time.Duration
+	verifyURL       string
+}
+
+// emailVerification is kept in the cache under the verification token,
+// the token only verifies the email it was sent to
+type emailVerification struct {
+	UserID uint64 `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+// NewVerificationService creates a new verification service instance.
+// The verification token is appended to the verify URL as the token query parameter, if the URL is given
+func NewVerificationService(
+	repo port.UserRepository,
+	notifier port.Notifier,
+	cache port.CacheRepository,
+	events port.EventPublisher,
+	tx port.Transactor,
+	verificationTTL time.Duration,
+	resendInterval time.Duration,
+	verifyURL string,
+) *VerificationService {
+	return &VerificationService{
+		repo,
+		notifier,
+		cache,
+		events,
+		tx,
+		verificationTTL,
+		resendInterval,
+		verifyURL,
+	}
+}
+
+// SendVerification issues an email verification token and sends it to the user.
+// The token is kept in the cache until it expires or is used
+func (vs *VerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	token, err := util.GenerateToken()
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	cacheKey := util.GenerateCacheKey("email_verification", util.HashToken(token))
+	verificationSerialized, err := util.Serialize(emailVerification{
+		UserID: user.ID,
+		Email:  user.Email,
+	})
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = vs.cache.Set(ctx, cacheKey, verificationSerialized, vs.verificationTTL)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = vs.notifier.Notify(ctx, vs.verificationNotification(user, token))
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email of the user the verification token was issued to as verified.
+// The token is rejected if the user changed the email since it was issued.
+// The user is locked until the verification is stored and announced
+func (vs *VerificationService) VerifyEmail(ctx context.Context, token []byte) error {
+	var verification emailVerification
+
+	cacheKey := util.GenerateCacheKey("email_verification", util.HashToken(token))
+	cachedVerification, err := vs.cache.Get(ctx, cacheKey)
+	if err != nil {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	err = util.Deserialize(cachedVerification, &verification)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	userID := verification.UserID
+
+	err = withinTx(ctx, vs.tx, func(ctx context.Context) error {
+		user, err := vs.repo.GetUserByIDForUpdate(ctx, userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return domain.ErrInvalidVerificationToken
+			}
+			return domain.ErrInternal
+		}
+
+		if !strings.EqualFold(user.Email, verification.Email) {
+			return domain.ErrInvalidVerificationToken
+		}
+
+		verifiedAt := time.Now()
+
+		_, err = vs.repo.UpdateUser(ctx, &domain.User{
+			ID:         userID,
+			VerifiedAt: &verifiedAt,
+		})
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		err = publishEvent(ctx, vs.events, domain.EventUserUpdated, userID, map[string]any{
+			"changed_fields": []string{"verified_at"},
+		})
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = vs.cache.Delete(ctx, cacheKey)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = vs.cache.Delete(ctx, util.GenerateCacheKey("user", userID))
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = vs.cache.DeleteByPrefix(ctx, "users:*")
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ResendVerification sends a new email verification token at most once per resend interval.
+// Unknown and already verified emails are ignored to not disclose which emails are registered
+func (vs *VerificationService) ResendVerification(ctx context.Context, email string) error {
+	cacheKey := util.GenerateCacheKey("verification_resend", strings.ToLower(email))
+	_, err := vs.cache.Get(ctx, cacheKey)
+	if err == nil {
+		return domain.ErrVerificationRateLimited
+	}
+
+	sentAtSerialized, err := util.Serialize(time.Now().Unix())
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	err = vs.cache.Set(ctx, cacheKey, sentAtSerialized, vs.resendInterval)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	user, err := vs.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil
+		}
+		return domain.ErrInternal
+	}
+
+	if user.VerifiedAt != nil {
+		return nil
+	}
+
+	return vs.SendVerification(ctx, user)
+}
+
+// verificationNotification composes the email verification notification
+func (vs *VerificationService) verificationNotification(user *domain.User, token []byte) *domain.Notification {
+	action := fmt.Sprintf("use the following token to verify your email address: %s", token)
+
+	verifyURL, err := url.Parse(vs.verifyURL)
+	if vs.verifyURL != "" && err == nil {
+		query := verifyURL.Query()
+		query.Set("token", string(token))
+		verifyURL.RawQuery = query.Encode()
+
+		action = fmt.Sprintf("follow the link to verify your email address: %s", verifyURL)
+	}
+
+	return &domain.Notification{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nThank you for signing up. Please %s\n\nIt expires in %s.\n",
+			user.Name,
+			action,
+			vs.verificationTTL,
+		),
+	}
+}
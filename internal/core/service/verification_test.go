@@ -0,0 +1,484 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang/mock/gomock"
+	"golang-hexagon/internal/adapter/publisher/memory"
+	"golang-hexagon/internal/core/domain"
+	"golang-hexagon/internal/core/port/mock"
+	"golang-hexagon/internal/core/service"
+	"golang-hexagon/internal/core/util"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const verifyURL = "https://example.com/v1/users/verify"
+
+type sendVerificationTestedInput struct {
+	user *domain.User
+}
+
+type sendVerificationExpectedOutput struct {
+	err error
+}
+
+func TestVerificationService_SendVerification(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Name:  gofakeit.Name(),
+		Email: gofakeit.Email(),
+	}
+	verificationSerialized, _ := util.Serialize(struct {
+		UserID uint64 `json:"user_id"`
+		Email  string `json:"email"`
+	}{user.ID, user.Email})
+
+	testCases := []struct {
+		desc     string
+		mocks    func(notifier *mock.MockNotifier, cache *mock.MockCacheRepository)
+		input    sendVerificationTestedInput
+		expected sendVerificationExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(notifier *mock.MockNotifier, cache *mock.MockCacheRepository) {
+				var cacheKey string
+
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Eq(verificationSerialized), gomock.Eq(time.Hour)).
+					DoAndReturn(func(_ context.Context, key string, _ []byte, _ time.Duration) error {
+						cacheKey = key
+						return nil
+					})
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, notification *domain.Notification) error {
+						if notification.To != user.Email {
+							t.Errorf("expected the notification to be sent to %s; got %s", user.Email, notification.To)
+						}
+						i := strings.Index(notification.Body, verifyURL+"?token=")
+						if i < 0 {
+							t.Fatalf("expected the notification to contain the verification link; got %q", notification.Body)
+						}
+						token := strings.Fields(notification.Body[i+len(verifyURL+"?token="):])[0]
+						if util.GenerateCacheKey("email_verification", util.HashToken([]byte(token))) != cacheKey {
+							t.Errorf("expected the notified token to match the cached hash")
+						}
+						return nil
+					})
+			},
+			input: sendVerificationTestedInput{
+				user: user,
+			},
+			expected: sendVerificationExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_SetCache",
+			mocks: func(notifier *mock.MockNotifier, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Eq(verificationSerialized), gomock.Eq(time.Hour)).
+					Return(domain.ErrInternal)
+			},
+			input: sendVerificationTestedInput{
+				user: user,
+			},
+			expected: sendVerificationExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+		{
+			desc: "Fail_Notify",
+			mocks: func(notifier *mock.MockNotifier, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Eq(verificationSerialized), gomock.Eq(time.Hour)).
+					Return(nil)
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Return(errors.New("smtp error"))
+			},
+			input: sendVerificationTestedInput{
+				user: user,
+			},
+			expected: sendVerificationExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			notifier := mock.NewMockNotifier(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(notifier, cache)
+
+			verificationService := service.NewVerificationService(userRepo, notifier, cache, memory.New(), newTransactor(ctrl), time.Hour, time.Minute, verifyURL)
+
+			err := verificationService.SendVerification(ctx, tc.input.user)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
+
+type verifyEmailTestedInput struct {
+	token []byte
+}
+
+type verifyEmailExpectedOutput struct {
+	published bool
+	err       error
+}
+
+func TestVerificationService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	token, _ := util.GenerateToken()
+	userID := gofakeit.Uint64()
+	user := &domain.User{
+		ID:    userID,
+		Email: gofakeit.Email(),
+	}
+	verificationSerialized, _ := util.Serialize(struct {
+		UserID uint64 `json:"user_id"`
+		Email  string `json:"email"`
+	}{userID, user.Email})
+	tokenCacheKey := util.GenerateCacheKey("email_verification", util.HashToken(token))
+	userCacheKey := util.GenerateCacheKey("user", userID)
+
+	testCases := []struct {
+		desc     string
+		mocks    func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository)
+		input    verifyEmailTestedInput
+		expected verifyEmailExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(verificationSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(user, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, user *domain.User) (*domain.User, error) {
+						if user.ID != userID || user.VerifiedAt == nil {
+							t.Errorf("expected user %d to be marked as verified; got %+v", userID, user)
+						}
+						return user, nil
+					})
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					Delete(gomock.Any(), gomock.Eq(userCacheKey)).
+					Return(nil)
+				cache.EXPECT().
+					DeleteByPrefix(gomock.Any(), gomock.Eq("users:*")).
+					Return(nil)
+			},
+			input: verifyEmailTestedInput{
+				token: token,
+			},
+			expected: verifyEmailExpectedOutput{
+				published: true,
+				err:       nil,
+			},
+		},
+		{
+			desc: "Fail_InvalidToken",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: verifyEmailTestedInput{
+				token: token,
+			},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInvalidVerificationToken,
+			},
+		},
+		{
+			desc: "Fail_EmailChanged",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(verificationSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(&domain.User{ID: userID, Email: gofakeit.Email()}, nil)
+			},
+			input: verifyEmailTestedInput{
+				token: token,
+			},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInvalidVerificationToken,
+			},
+		},
+		{
+			desc: "Fail_UserNotFound",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(verificationSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: verifyEmailTestedInput{
+				token: token,
+			},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInvalidVerificationToken,
+			},
+		},
+		{
+			desc: "Fail_UpdateUser",
+			mocks: func(userRepo *mock.MockUserRepository, cache *mock.MockCacheRepository) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(tokenCacheKey)).
+					Return(verificationSerialized, nil)
+				userRepo.EXPECT().
+					GetUserByIDForUpdate(gomock.Any(), gomock.Eq(userID)).
+					Return(user, nil)
+				userRepo.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Return(nil, domain.ErrInternal)
+			},
+			input: verifyEmailTestedInput{
+				token: token,
+			},
+			expected: verifyEmailExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			notifier := mock.NewMockNotifier(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, cache)
+
+			events := memory.New()
+
+			verificationService := service.NewVerificationService(userRepo, notifier, cache, events, newTransactor(ctrl), time.Hour, time.Minute, verifyURL)
+
+			err := verificationService.VerifyEmail(ctx, tc.input.token)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+
+			published := events.Events()
+			if tc.expected.published && (len(published) != 1 || published[0].Type != domain.EventUserUpdated) {
+				t.Errorf("[case: %s] expected to publish a %q event; got %v", tc.desc, domain.EventUserUpdated, published)
+			}
+			if tc.expected.published && len(published) == 1 && !reflect.DeepEqual(published[0].Data, map[string]any{"changed_fields": []string{"verified_at"}}) {
+				t.Errorf("[case: %s] expected the %q field to change; got %v", tc.desc, "verified_at", published[0].Data)
+			}
+			if !tc.expected.published && len(published) != 0 {
+				t.Errorf("[case: %s] expected to publish no events; got %v", tc.desc, published)
+			}
+		})
+	}
+}
+
+type resendVerificationTestedInput struct {
+	email string
+}
+
+type resendVerificationExpectedOutput struct {
+	err error
+}
+
+func TestVerificationService_ResendVerification(t *testing.T) {
+	ctx := context.Background()
+	verifiedAt := time.Now()
+	user := &domain.User{
+		ID:    gofakeit.Uint64(),
+		Email: gofakeit.Email(),
+	}
+	verifiedUser := &domain.User{
+		ID:         user.ID,
+		Email:      user.Email,
+		VerifiedAt: &verifiedAt,
+	}
+	resendCacheKey := util.GenerateCacheKey("verification_resend", strings.ToLower(user.Email))
+
+	testCases := []struct {
+		desc  string
+		mocks func(
+			userRepo *mock.MockUserRepository,
+			notifier *mock.MockNotifier,
+			cache *mock.MockCacheRepository,
+		)
+		input    resendVerificationTestedInput
+		expected resendVerificationExpectedOutput
+	}{
+		{
+			desc: "Success",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				notifier *mock.MockNotifier,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(resendCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(resendCacheKey), gomock.Any(), gomock.Eq(time.Minute)).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(user, nil)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(time.Hour)).
+					Return(nil)
+				notifier.EXPECT().
+					Notify(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			input: resendVerificationTestedInput{
+				email: user.Email,
+			},
+			expected: resendVerificationExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_AlreadyVerified",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				notifier *mock.MockNotifier,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(resendCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(resendCacheKey), gomock.Any(), gomock.Eq(time.Minute)).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(verifiedUser, nil)
+			},
+			input: resendVerificationTestedInput{
+				email: user.Email,
+			},
+			expected: resendVerificationExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Success_UnknownEmail",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				notifier *mock.MockNotifier,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(resendCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(resendCacheKey), gomock.Any(), gomock.Eq(time.Minute)).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(nil, domain.ErrDataNotFound)
+			},
+			input: resendVerificationTestedInput{
+				email: user.Email,
+			},
+			expected: resendVerificationExpectedOutput{
+				err: nil,
+			},
+		},
+		{
+			desc: "Fail_RateLimited",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				notifier *mock.MockNotifier,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(resendCacheKey)).
+					Return([]byte("1"), nil)
+			},
+			input: resendVerificationTestedInput{
+				email: user.Email,
+			},
+			expected: resendVerificationExpectedOutput{
+				err: domain.ErrVerificationRateLimited,
+			},
+		},
+		{
+			desc: "Fail_InternalError",
+			mocks: func(
+				userRepo *mock.MockUserRepository,
+				notifier *mock.MockNotifier,
+				cache *mock.MockCacheRepository,
+			) {
+				cache.EXPECT().
+					Get(gomock.Any(), gomock.Eq(resendCacheKey)).
+					Return(nil, domain.ErrDataNotFound)
+				cache.EXPECT().
+					Set(gomock.Any(), gomock.Eq(resendCacheKey), gomock.Any(), gomock.Eq(time.Minute)).
+					Return(nil)
+				userRepo.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Return(nil, domain.ErrInternal)
+			},
+			input: resendVerificationTestedInput{
+				email: user.Email,
+			},
+			expected: resendVerificationExpectedOutput{
+				err: domain.ErrInternal,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mock.NewMockUserRepository(ctrl)
+			notifier := mock.NewMockNotifier(ctrl)
+			cache := mock.NewMockCacheRepository(ctrl)
+
+			tc.mocks(userRepo, notifier, cache)
+
+			verificationService := service.NewVerificationService(userRepo, notifier, cache, memory.New(), newTransactor(ctrl), time.Hour, time.Minute, verifyURL)
+
+			err := verificationService.ResendVerification(ctx, tc.input.email)
+			if !errors.Is(err, tc.expected.err) {
+				t.Errorf("[case: %s] expected to get %q; got %q", tc.desc, tc.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// tokenSize is the number of random bytes in an opaque token
+	tokenSize = 32
+	// recoveryCodeSize is the number of random bytes in a recovery code
+	recoveryCodeSize = 10
+)
+
+// recoveryCodeEncoding encodes recovery codes with lowercase letters and digits that are easy to type
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// GenerateToken generates a random url-safe opaque token
+func GenerateToken() ([]byte, error) {
+	b := make([]byte, tokenSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, base64.RawURLEncoding.EncodedLen(tokenSize))
+	base64.RawURLEncoding.Encode(token, b)
+
+	return token, nil
+}
+
+// HashToken hashes an opaque token using sha256 so it can be stored and looked up
+func HashToken(token []byte) string {
+	sum := sha256.Sum256(token)
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateRecoveryCode generates a random one-time recovery code formatted as xxxxxxxx-xxxxxxxx
+func GenerateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	code := recoveryCodeEncoding.EncodeToString(b)
+
+	return code[:len(code)/2] + "-" + code[len(code)/2:], nil
+}
+
+// HashRecoveryCode hashes a recovery code ignoring its case, spaces and dashes
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	return HashToken([]byte(code))
+}
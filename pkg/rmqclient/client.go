@@ -0,0 +1,340 @@
+// Package rmqclient is a client for the RabbitMQ adapter of the user service.
+// It publishes requests to the queue of the service and waits for the response
+// that carries the same correlation id on an exclusive reply queue
+package rmqclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+const contentType = "application/json"
+
+// DefaultTimeout is the time a call waits for its response if no timeout is given
+const DefaultTimeout = 10 * time.Second
+
+// ErrClosed is returned by calls on a closed client or when the reply queue is closed while waiting
+var ErrClosed = errors.New("rmq client is closed")
+
+// Client performs RPC calls against the RabbitMQ adapter of the user service
+type Client struct {
+	ch      *amqp.Channel
+	queue   string
+	replyTo string
+	appID   string
+	timeout time.Duration
+
+	seq     atomic.Uint64
+	mu      sync.Mutex
+	pending map[string]chan amqp.Delivery
+	closed  bool
+	done    chan struct{}
+}
+
+// Options configures the client
+type Options struct {
+	// Queue is the queue the user service consumes requests from
+	Queue string
+	// AppID is set as the app id of the requests, e.g. to tell clients apart in the broker logs
+	AppID string
+	// Timeout is the time a call waits for its response, DefaultTimeout if zero
+	Timeout time.Duration
+}
+
+// New creates a new client on its own channel of the connection
+func New(conn *amqp.Connection, opts Options) (*Client, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	replyQueue, err := ch.QueueDeclare(
+		"",    // name, generated by the server
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no wait
+		nil,   // args
+	)
+	if err != nil {
+		_ = ch.Close()
+		return nil, err
+	}
+
+	replies, err := ch.Consume(
+		replyQueue.Name, // queue
+		"",              // consumer
+		true,            // auto ack
+		true,            // exclusive
+		false,           // no local
+		false,           // no wait
+		nil,             // args
+	)
+	if err != nil {
+		_ = ch.Close()
+		return nil, err
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	c := &Client{
+		ch:      ch,
+		queue:   opts.Queue,
+		replyTo: replyQueue.Name,
+		appID:   opts.AppID,
+		timeout: timeout,
+		pending: make(map[string]chan amqp.Delivery),
+		done:    make(chan struct{}),
+	}
+
+	go c.dispatch(replies)
+
+	return c, nil
+}
+
+// Close closes the channel of the client, pending calls fail with ErrClosed
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	err := c.ch.Close()
+	<-c.done
+
+	return err
+}
+
+// Login logs the user in. If the user has MFA enabled, the response holds the MFA challenge instead of the tokens
+func (c *Client) Login(ctx context.Context, email, password string) (*LoginResponse, error) {
+	var rsp LoginResponse
+
+	err := c.call(ctx, &request{
+		Type:     "login",
+		Email:    email,
+		Password: []byte(password),
+	}, &rsp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsp, nil
+}
+
+// Signup registers a new user
+func (c *Client) Signup(ctx context.Context, name, email, password string) (*User, error) {
+	var user User
+
+	err := c.call(ctx, &request{
+		Type:     "signup",
+		Name:     name,
+		Email:    email,
+		Password: []byte(password),
+	}, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Update updates the user on behalf of the owner of the access token. Empty fields are left unchanged
+func (c *Client) Update(ctx context.Context, token string, req UpdateRequest) (*User, error) {
+	var user User
+
+	err := c.call(ctx, &request{
+		Type:     "update",
+		Token:    token,
+		UID:      req.ID,
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: []byte(req.Password),
+		Role:     req.Role,
+		Version:  req.Version,
+	}, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Delete deletes the user by ID on behalf of the owner of the access token, the user can be restored until it is purged.
+// A non-zero version is the version the user was read at, the user is not deleted if it has changed since
+func (c *Client) Delete(ctx context.Context, token string, id, version uint64) error {
+	return c.call(ctx, &request{
+		Type:    "delete",
+		Token:   token,
+		UID:     id,
+		Version: version,
+	}, nil)
+}
+
+// List lists users with pagination on behalf of the owner of the access token.
+// Users the owner may not read the details of only have their public profile set
+func (c *Client) List(ctx context.Context, token string, skip, limit uint64) ([]User, error) {
+	var users []User
+
+	err := c.call(ctx, &request{
+		Type:   "list",
+		Token:  token,
+		Offset: skip,
+		Limit:  limit,
+	}, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// ListDeleted lists the deleted users that are not purged yet with pagination on behalf of the owner of the access token
+func (c *Client) ListDeleted(ctx context.Context, token string, skip, limit uint64) ([]User, error) {
+	var users []User
+
+	err := c.call(ctx, &request{
+		Type:   "list_deleted",
+		Token:  token,
+		Offset: skip,
+		Limit:  limit,
+	}, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// Restore restores the deleted user by ID on behalf of the owner of the access token
+func (c *Client) Restore(ctx context.Context, token string, id uint64) (*User, error) {
+	var user User
+
+	err := c.call(ctx, &request{
+		Type:  "restore",
+		Token: token,
+		UID:   id,
+	}, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Purge permanently deletes the deleted user by ID on behalf of the owner of the access token
+func (c *Client) Purge(ctx context.Context, token string, id uint64) error {
+	return c.call(ctx, &request{
+		Type:  "purge",
+		Token: token,
+		UID:   id,
+	}, nil)
+}
+
+// call publishes the request and decodes the message of its response into out if it is not nil
+func (c *Client) call(ctx context.Context, req *request, out any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	correlationID := strconv.FormatUint(c.seq.Add(1), 10)
+	reply := make(chan amqp.Delivery, 1)
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.pending[correlationID] = reply
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, correlationID)
+		c.mu.Unlock()
+	}()
+
+	err = c.ch.Publish(
+		"",      // exchange
+		c.queue, // routing key
+		false,   // mandatory
+		false,   // immediate
+		amqp.Publishing{
+			ContentType:   contentType,
+			CorrelationId: correlationID,
+			ReplyTo:       c.replyTo,
+			AppId:         c.appID,
+			Body:          body,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	var delivery amqp.Delivery
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		return ErrClosed
+	case delivery = <-reply:
+	}
+
+	var rsp response
+	err = json.Unmarshal(delivery.Body, &rsp)
+	if err != nil {
+		return err
+	}
+
+	if !rsp.Success {
+		return &Error{
+			Status:     rsp.Status,
+			Message:    rsp.Error,
+			Violations: rsp.Violations,
+		}
+	}
+
+	if out == nil || rsp.Message == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(rsp.Message), out)
+}
+
+// dispatch routes the responses to the pending calls by their correlation id
+// until the reply queue is closed. Responses of calls that are no longer pending and
+// duplicate responses of a call are dropped, so they never block the other calls
+func (c *Client) dispatch(replies <-chan amqp.Delivery) {
+	defer close(c.done)
+
+	for delivery := range replies {
+		c.mu.Lock()
+		reply, ok := c.pending[delivery.CorrelationId]
+		c.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		select {
+		case reply <- delivery:
+		default:
+		}
+	}
+}
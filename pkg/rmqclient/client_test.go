@@ -0,0 +1,59 @@
+package rmqclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+func TestClient_Dispatch(t *testing.T) {
+	c := &Client{
+		pending: map[string]chan amqp.Delivery{
+			"1": make(chan amqp.Delivery, 1),
+			"2": make(chan amqp.Delivery, 1),
+		},
+		done: make(chan struct{}),
+	}
+
+	replies := make(chan amqp.Delivery)
+	go c.dispatch(replies)
+
+	// the responses arrive out of order, with a response to an unknown call and a duplicate in between
+	deliveries := []amqp.Delivery{
+		{CorrelationId: "2", Body: []byte("second")},
+		{CorrelationId: "unknown", Body: []byte("unknown")},
+		{CorrelationId: "1", Body: []byte("first")},
+		{CorrelationId: "1", Body: []byte("duplicate")},
+	}
+	for _, delivery := range deliveries {
+		select {
+		case replies <- delivery:
+		case <-time.After(time.Second):
+			t.Fatalf("expected the dispatch to take the response %q", delivery.CorrelationId)
+		}
+	}
+	close(replies)
+
+	select {
+	case <-c.done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the dispatch to stop once the reply queue is closed")
+	}
+
+	expected := map[string]string{
+		"1": "first",
+		"2": "second",
+	}
+	for correlationID, body := range expected {
+		reply := c.pending[correlationID]
+		if len(reply) != 1 {
+			t.Fatalf("expected the call %q to get one response; got %d", correlationID, len(reply))
+		}
+
+		delivery := <-reply
+		if string(delivery.Body) != body {
+			t.Errorf("expected the call %q to get %q; got %q", correlationID, body, delivery.Body)
+		}
+	}
+}
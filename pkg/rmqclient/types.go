@@ -0,0 +1,84 @@
+package rmqclient
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// request is the message consumed by the user service
+	request struct {
+		Type     string `json:"type"`
+		Token    string `json:"token,omitempty"`
+		UID      uint64 `json:"uid,omitempty"`
+		Name     string `json:"name,omitempty"`
+		Email    string `json:"email,omitempty"`
+		Password []byte `json:"password,omitempty"`
+		Role     string `json:"role,omitempty"`
+		Offset   uint64 `json:"offset,omitempty"`
+		Limit    uint64 `json:"limit,omitempty"`
+		Version  uint64 `json:"version,omitempty"`
+	}
+
+	// response is the message sent back by the user service
+	response struct {
+		Success    bool        `json:"success"`
+		Status     int         `json:"statusCode"`
+		Message    string      `json:"message"`
+		Error      string      `json:"error"`
+		Violations []Violation `json:"violations"`
+	}
+)
+
+// LoginResponse holds the token pair, or the MFA challenge if the user has MFA enabled
+type LoginResponse struct {
+	AccessToken      string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	ExpiresIn        int64     `json:"expires_in"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	MFARequired      bool      `json:"mfa_required"`
+	MFAToken         string    `json:"mfa_token"`
+}
+
+// User is a user as returned by the user service
+type User struct {
+	ID         uint64
+	Name       string
+	Email      string
+	Role       string
+	VerifiedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Version    uint64
+	DeletedAt  *time.Time
+}
+
+// UpdateRequest holds the changes of a user, empty fields are left unchanged.
+// A non-zero version is the version the user was read at, the update is rejected if the user has changed since
+type UpdateRequest struct {
+	ID       uint64
+	Name     string
+	Email    string
+	Password string
+	Role     string
+	Version  uint64
+}
+
+// Violation is a broken password policy rule
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error is the error response of the user service
+type Error struct {
+	Status     int
+	Message    string
+	Violations []Violation
+}
+
+// Error returns the status code and the message of the error
+func (e *Error) Error() string {
+	return fmt.Sprintf("rmq: %d %s", e.Status, e.Message)
+}